@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Android strings.xml
+// <string>, <string-array>, <plurals>를 각각 문자열, 배열, 복수형 객체로 읽는다.
+// 대상 파일은 소스 파일의 텍스트에서 값 부분만 바꿔 쓰므로 주석, 속성, 다른 리소스와 서식이 그대로 남는다.
+// translatable="false"인 리소스는 대상 파일에서 빼고, "@string/..." 같은 참조 값은 번역하지 않는다.
+type androidFormat struct{}
+
+type androidResource struct {
+	name         string
+	kind         string // "string", "string-array", "plurals"
+	translatable bool
+	start, end   int    // 요소 전체의 바이트 범위
+	inner        [2]int // 요소 내용의 바이트 범위
+	items        []androidItem
+}
+
+type androidItem struct {
+	quantity   string
+	start, end int
+	inner      [2]int
+}
+
+type androidSource struct {
+	data      []byte
+	resources []*androidResource
+}
+
+func (androidFormat) Name() string { return "android" }
+
+func (androidFormat) Parse(data []byte, lang string) (*localeDocument, error) {
+	resources, comments, err := scanAndroidResources(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &localeDocument{Context: make(contextNotes), Plurals: make(map[string]bool), source: &androidSource{data: data, resources: resources}}
+	content := newOrderedMap()
+	for _, res := range resources {
+		if !res.translatable {
+			continue
+		}
+		path := joinPointer("", res.name)
+		switch res.kind {
+		case "string":
+			raw := string(data[res.inner[0]:res.inner[1]])
+			text := unescapeAndroid(raw)
+			if text == "" || isAndroidReference(raw) {
+				continue
+			}
+			content.Set(res.name, text)
+		case "string-array":
+			items := make([]interface{}, len(res.items))
+			for i, item := range res.items {
+				items[i] = unescapeAndroid(string(data[item.inner[0]:item.inner[1]]))
+			}
+			content.Set(res.name, items)
+		case "plurals":
+			forms := newOrderedMap()
+			for _, item := range res.items {
+				forms.Set(item.quantity, unescapeAndroid(string(data[item.inner[0]:item.inner[1]])))
+			}
+			content.Set(res.name, forms)
+			if _, ok := forms.Get("other"); ok {
+				doc.Plurals[path] = true
+			}
+		}
+		if comment := comments[res]; comment != "" {
+			doc.Context[path] = keyContext{Description: comment}
+		}
+	}
+	doc.Content = content
+	return doc, nil
+}
+
+// <resources> 바로 아래의 문자열 리소스와 각 리소스 바로 앞의 주석을 찾는다.
+func scanAndroidResources(data []byte) ([]*androidResource, map[*androidResource]string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+
+	var resources []*androidResource
+	comments := make(map[*androidResource]string)
+	var current *androidResource
+	var item *androidItem
+	var lastComment string
+	depth := 0
+
+	for {
+		offset := int(dec.InputOffset())
+		token, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		end := int(dec.InputOffset())
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case depth == 1 && t.Name.Local != "resources":
+				return nil, nil, fmt.Errorf("root element must be <resources>, got <%s>", t.Name.Local)
+			case depth == 2 && (t.Name.Local == "string" || t.Name.Local == "string-array" || t.Name.Local == "plurals"):
+				current = &androidResource{kind: t.Name.Local, translatable: true, start: offset, inner: [2]int{end, end}}
+				for _, attr := range t.Attr {
+					switch attr.Name.Local {
+					case "name":
+						current.name = attr.Value
+					case "translatable":
+						current.translatable = attr.Value != "false"
+					}
+				}
+				if current.name == "" {
+					return nil, nil, fmt.Errorf("<%s> without a name attribute", t.Name.Local)
+				}
+				if lastComment != "" {
+					comments[current] = lastComment
+				}
+			case depth == 3 && current != nil && current.kind != "string" && t.Name.Local == "item":
+				item = &androidItem{start: offset, inner: [2]int{end, end}}
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "quantity" {
+						item.quantity = attr.Value
+					}
+				}
+				if current.kind == "plurals" && !validPluralCategory[item.quantity] {
+					return nil, nil, fmt.Errorf("plurals %q: invalid quantity %q", current.name, item.quantity)
+				}
+			}
+			lastComment = ""
+		case xml.EndElement:
+			switch {
+			case depth == 2 && current != nil:
+				current.inner[1] = offset
+				current.end = end
+				resources = append(resources, current)
+				current = nil
+			case depth == 3 && item != nil:
+				item.inner[1] = offset
+				item.end = end
+				current.items = append(current.items, *item)
+				item = nil
+			}
+			depth--
+		case xml.Comment:
+			if depth == 1 {
+				lastComment = strings.Join(strings.Fields(string(t)), " ")
+			}
+		}
+	}
+	return resources, comments, nil
+}
+
+// "@string/app_name", "?attr/..." 같은 리소스 참조 (이스케이프된 "\@"는 제외)
+func isAndroidReference(raw string) bool {
+	text := strings.TrimSpace(raw)
+	return strings.HasPrefix(text, "@") || strings.HasPrefix(text, "?")
+}
+
+func (androidFormat) Render(doc *localeDocument, content interface{}, lang string) ([]byte, error) {
+	source := doc.source.(*androidSource)
+	data := source.data
+	translated, _ := content.(*orderedMap)
+	if translated == nil {
+		translated = newOrderedMap()
+	}
+
+	patch := &textPatch{data: data}
+
+	for _, res := range source.resources {
+		if !res.translatable {
+			patch.remove(res.start, res.end)
+			continue
+		}
+		value, ok := translated.Get(res.name)
+		switch res.kind {
+		case "string":
+			text, isText := value.(string)
+			switch {
+			case !ok && isAndroidReference(string(data[res.inner[0]:res.inner[1]])):
+				// 참조 값은 소스 그대로 둔다
+			case !ok || !isText:
+				// 번역되지 않은 문자열은 빼서 기본 리소스로 대체되게 한다
+				patch.remove(res.start, res.end)
+			default:
+				patch.replace(res.inner[0], res.inner[1], escapeAndroid(text))
+			}
+		case "string-array":
+			items, _ := value.([]interface{})
+			if len(items) != len(res.items) {
+				patch.remove(res.start, res.end)
+				continue
+			}
+			for i, item := range res.items {
+				text, _ := items[i].(string)
+				patch.replace(item.inner[0], item.inner[1], escapeAndroid(text))
+			}
+		case "plurals":
+			forms, _ := value.(*orderedMap)
+			if forms == nil || len(res.items) == 0 {
+				patch.remove(res.start, res.end)
+				continue
+			}
+			// 대상 언어의 범주로 <item>을 다시 만든다 (첫 항목의 들여쓰기 사용)
+			first, last := res.items[0], res.items[len(res.items)-1]
+			indent := ""
+			for i := first.start; i > 0 && (data[i-1] == ' ' || data[i-1] == '\t'); i-- {
+				indent = string(data[i-1]) + indent
+			}
+			var items []string
+			for _, category := range forms.Keys() {
+				text, _ := lookupString(forms, category)
+				items = append(items, fmt.Sprintf("<item quantity=%q>%s</item>", category, escapeAndroid(text)))
+			}
+			patch.replace(first.start, last.end, strings.Join(items, "\n"+indent))
+		}
+	}
+	return patch.finish(), nil
+}
+
+var androidEntityPattern = regexp.MustCompile(`^&(#[0-9]+|#x[0-9A-Fa-f]+|[A-Za-z][A-Za-z0-9]*);`)
+
+// Android 문자열 이스케이프를 푼다. 전체를 감싼 큰따옴표도 벗긴다.
+// 태그와 XML 엔티티는 그대로 두어 대상 파일에 같은 형태로 쓴다.
+func unescapeAndroid(raw string) string {
+	text := strings.TrimSpace(raw)
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		text = text[1 : len(text)-1]
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != '\\' || i+1 == len(text) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch text[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
+
+// 태그 밖의 텍스트에 Android 이스케이프와 XML 이스케이프를 적용한다.
+func escapeAndroid(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range tagPattern.FindAllStringIndex(text, -1) {
+		b.WriteString(escapeAndroidText(text[last:loc[0]], last == 0))
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(escapeAndroidText(text[last:], last == 0))
+	return b.String()
+}
+
+func escapeAndroidText(text string, atStart bool) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '"':
+			b.WriteString(`\"`)
+		case '<':
+			b.WriteString("&lt;")
+		case '&':
+			if androidEntityPattern.MatchString(text[i:]) {
+				b.WriteByte(c)
+			} else {
+				b.WriteString("&amp;")
+			}
+		case '@', '?':
+			if atStart && i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+)
+
+const testAndroidSource = `<resources>
+  <!-- greeting -->
+  <string name="a">Hi</string>
+  <string name="k" translatable="false">K</string>
+  <string name="ref">@string/a</string>
+  <string-array name="arr">
+    <item>One</item>
+    <item>Two</item>
+  </string-array>
+  <plurals name="p">
+    <item quantity="one">%d file</item>
+    <item quantity="other">%d files</item>
+  </plurals>
+</resources>
+`
+
+func TestAndroidFormatRoundTrip(t *testing.T) {
+	runFormatRoundTrips(t, []formatRoundTrip{
+		{
+			name:        "strings, arrays and plurals",
+			path:        "strings.xml",
+			source:      testAndroidSource,
+			wantContent: `{"a":"Hi","arr":["One","Two"],"p":{"one":"%d file","other":"%d files"}}`,
+			translated:  `{"a":"L'ami \"<b>x</b>\"","arr":["Un","Deux"],"p":{"one":"%d fichier","many":"%d fichiers","other":"%d fichiers"}}`,
+			want: `<resources>
+  <!-- greeting -->
+  <string name="a">L\'ami \"<b>x</b>\"</string>
+  <string name="ref">@string/a</string>
+  <string-array name="arr">
+    <item>Un</item>
+    <item>Deux</item>
+  </string-array>
+  <plurals name="p">
+    <item quantity="one">%d fichier</item>
+    <item quantity="many">%d fichiers</item>
+    <item quantity="other">%d fichiers</item>
+  </plurals>
+</resources>
+`,
+		},
+		{
+			name:        "untranslated resources are left out",
+			path:        "strings.xml",
+			source:      testAndroidSource,
+			wantContent: `{"a":"Hi","arr":["One","Two"],"p":{"one":"%d file","other":"%d files"}}`,
+			translated:  `{"arr":["Un"]}`,
+			want: `<resources>
+  <!-- greeting -->
+  <string name="ref">@string/a</string>
+</resources>
+`,
+			wantRead: `{}`,
+		},
+	})
+}
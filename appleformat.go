@@ -0,0 +1,496 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// iOS/macOS Localizable.strings
+// "key" = "value"; 항목의 값 리터럴만 바꿔 쓰므로 주석과 순서, 서식이 그대로 남는다.
+// 항목 바로 앞의 주석은 맥락으로 모델에 전달한다. UTF-16 파일은 UTF-8로 읽고 UTF-8로 쓴다.
+type stringsFormat struct{}
+
+type stringsEntry struct {
+	key        string
+	value      string
+	start, end int    // 항목 범위 (키 시작부터 ";"까지)
+	literal    [2]int // 값 리터럴 범위 (따옴표 포함)
+	comment    string
+}
+
+type stringsSource struct {
+	data    []byte
+	entries []stringsEntry
+}
+
+// Xcode가 주석이 없는 항목에 넣는 기본 주석
+const xcodeNoComment = "No comment provided by engineer."
+
+func (stringsFormat) Name() string { return "strings" }
+
+func (stringsFormat) Parse(data []byte, lang string) (*localeDocument, error) {
+	data, err := decodeAppleText(data)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := parseStrings(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &localeDocument{Content: newOrderedMap(), Context: make(contextNotes), source: &stringsSource{data: data, entries: entries}}
+	content := doc.Content.(*orderedMap)
+	for _, entry := range entries {
+		content.Set(entry.key, entry.value)
+		if entry.comment != "" && entry.comment != xcodeNoComment {
+			doc.Context[joinPointer("", entry.key)] = keyContext{Description: entry.comment}
+		}
+	}
+	return doc, nil
+}
+
+func (stringsFormat) Render(doc *localeDocument, content interface{}, lang string) ([]byte, error) {
+	source := doc.source.(*stringsSource)
+	translated, _ := content.(*orderedMap)
+	if translated == nil {
+		translated = newOrderedMap()
+	}
+
+	patch := &textPatch{data: source.data}
+	for _, entry := range source.entries {
+		// 번역되지 않은 항목은 빼서 개발 언어 값으로 대체되게 한다
+		text, ok := lookupString(translated, entry.key)
+		if !ok {
+			patch.remove(entry.start, entry.end)
+			continue
+		}
+		patch.replace(entry.literal[0], entry.literal[1], quoteAppleString(text))
+	}
+	return patch.finish(), nil
+}
+
+// UTF-16(BOM 있음) 파일을 UTF-8로 바꾸고 UTF-8 BOM을 뗀다.
+func decodeAppleText(data []byte) ([]byte, error) {
+	var bigEndian bool
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		bigEndian = true
+	default:
+		data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+		if !utf8.Valid(data) {
+			return nil, fmt.Errorf("file is not valid UTF-8 or UTF-16")
+		}
+		return data, nil
+	}
+
+	data = data[2:]
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("invalid UTF-16 file")
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return []byte(string(utf16.Decode(units))), nil
+}
+
+func parseStrings(data []byte) ([]stringsEntry, error) {
+	var entries []stringsEntry
+	var comment string
+	s := string(data)
+	i := 0
+
+	lineOf := func(pos int) int { return strings.Count(s[:pos], "\n") + 1 }
+	skipSpace := func() {
+		for i < len(s) && strings.IndexByte(" \t\r\n", s[i]) >= 0 {
+			i++
+		}
+	}
+
+	for {
+		skipSpace()
+		if i >= len(s) {
+			break
+		}
+		switch {
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", lineOf(i))
+			}
+			comment = strings.Join(strings.Fields(s[i+2:i+2+end]), " ")
+			i += end + 4
+			continue
+		case strings.HasPrefix(s[i:], "//"):
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				end = len(s) - i
+			}
+			comment = strings.TrimSpace(s[i+2 : i+end])
+			i += end
+			continue
+		}
+
+		entry := stringsEntry{start: i, comment: comment}
+		comment = ""
+		key, next, err := readAppleToken(s, i)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineOf(i), err)
+		}
+		entry.key, i = key, next
+
+		skipSpace()
+		if i >= len(s) || s[i] != '=' {
+			return nil, fmt.Errorf("line %d: expected '=' after %q", lineOf(i), key)
+		}
+		i++
+		skipSpace()
+		if i >= len(s) || s[i] != '"' {
+			return nil, fmt.Errorf("line %d: expected a quoted value for %q", lineOf(i), key)
+		}
+		entry.literal[0] = i
+		value, next, err := readAppleToken(s, i)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineOf(i), err)
+		}
+		entry.value, i = value, next
+		entry.literal[1] = i
+
+		skipSpace()
+		if i >= len(s) || s[i] != ';' {
+			return nil, fmt.Errorf("line %d: expected ';' after %q", lineOf(i), key)
+		}
+		i++
+		entry.end = i
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// 따옴표 문자열 또는 따옴표 없는 키를 읽는다.
+func readAppleToken(s string, i int) (string, int, error) {
+	if s[i] != '"' {
+		start := i
+		for i < len(s) && strings.IndexByte(" \t\r\n=;", s[i]) < 0 {
+			i++
+		}
+		if i == start {
+			return "", i, fmt.Errorf("unexpected %q", s[i])
+		}
+		return s[start:i], i, nil
+	}
+
+	var b strings.Builder
+	for i++; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'U', 'u':
+				if i+4 < len(s) {
+					if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+						b.WriteRune(rune(r))
+						i += 4
+						continue
+					}
+				}
+				b.WriteByte(s[i])
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", i, fmt.Errorf("unterminated string")
+}
+
+var appleEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func quoteAppleString(s string) string {
+	return `"` + appleEscaper.Replace(s) + `"`
+}
+
+// iOS/macOS .stringsdict (plist)
+// 항목마다 NSStringLocalizedFormatKey와 복수형 변수의 범주 문자열만 번역하고,
+// NSStringFormatSpecTypeKey 같은 형식 키는 소스 값을 그대로 쓴다.
+type stringsdictFormat struct{}
+
+// 문자열이 아닌 plist 값 (integer, true 등)
+type plistRaw struct {
+	tag  string
+	text string
+}
+
+type stringsdictSource struct {
+	header []byte // <plist> 앞의 XML 선언과 DOCTYPE
+	root   *orderedMap
+}
+
+const (
+	stringsdictFormatKey  = "NSStringLocalizedFormatKey"
+	stringsdictSpecKey    = "NSStringFormatSpecTypeKey"
+	stringsdictPluralRule = "NSStringPluralRuleType"
+)
+
+func (stringsdictFormat) Name() string { return "stringsdict" }
+
+func (stringsdictFormat) Parse(data []byte, lang string) (*localeDocument, error) {
+	root, err := parsePlist(data)
+	if err != nil {
+		return nil, err
+	}
+	header := data
+	if i := bytes.Index(data, []byte("<plist")); i >= 0 {
+		header = data[:i]
+	}
+
+	doc := &localeDocument{Context: make(contextNotes), Plurals: make(map[string]bool), source: &stringsdictSource{header: header, root: root}}
+	content := newOrderedMap()
+	for _, key := range root.Keys() {
+		value, _ := root.Get(key)
+		entry, ok := value.(*orderedMap)
+		if !ok {
+			continue
+		}
+		path := joinPointer("", key)
+		translatable := newOrderedMap()
+		for _, field := range entry.Keys() {
+			fieldValue, _ := entry.Get(field)
+			switch v := fieldValue.(type) {
+			case string:
+				if field == stringsdictFormatKey {
+					translatable.Set(field, v)
+				}
+			case *orderedMap:
+				if spec, _ := lookupString(v, stringsdictSpecKey); spec != stringsdictPluralRule {
+					continue
+				}
+				forms := newOrderedMap()
+				for _, category := range v.Keys() {
+					if text, ok := lookupString(v, category); ok && validPluralCategory[category] {
+						forms.Set(category, text)
+					}
+				}
+				translatable.Set(field, forms)
+				if _, ok := forms.Get("other"); ok {
+					doc.Plurals[joinPointer(path, field)] = true
+				}
+			}
+		}
+		if translatable.Len() > 0 {
+			content.Set(key, translatable)
+		}
+	}
+	doc.Content = content
+	return doc, nil
+}
+
+func (stringsdictFormat) Render(doc *localeDocument, content interface{}, lang string) ([]byte, error) {
+	source := doc.source.(*stringsdictSource)
+	translated, _ := content.(*orderedMap)
+	if translated == nil {
+		translated = newOrderedMap()
+	}
+
+	// 번역된 항목만 남기고, 번역 값을 소스 항목에 덮어쓴다
+	root := newOrderedMap()
+	for _, key := range source.root.Keys() {
+		value, _ := source.root.Get(key)
+		entry, isEntry := value.(*orderedMap)
+		translatedEntry, ok := translated.Get(key)
+		translatedMap, _ := translatedEntry.(*orderedMap)
+		if !isEntry || !ok || translatedMap == nil {
+			continue
+		}
+
+		merged := newOrderedMap()
+		for _, field := range entry.Keys() {
+			fieldValue, _ := entry.Get(field)
+			replacement, ok := translatedMap.Get(field)
+			if !ok {
+				merged.Set(field, fieldValue)
+				continue
+			}
+			variable, isVariable := fieldValue.(*orderedMap)
+			forms, isForms := replacement.(*orderedMap)
+			if !isVariable || !isForms {
+				merged.Set(field, replacement)
+				continue
+			}
+			// 형식 키는 소스에서, 범주 문자열은 번역에서 가져온다
+			mergedVariable := newOrderedMap()
+			for _, name := range variable.Keys() {
+				if !validPluralCategory[name] {
+					v, _ := variable.Get(name)
+					mergedVariable.Set(name, v)
+				}
+			}
+			for _, category := range forms.Keys() {
+				v, _ := forms.Get(category)
+				mergedVariable.Set(category, v)
+			}
+			merged.Set(field, mergedVariable)
+		}
+		root.Set(key, merged)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(source.header)
+	buf.WriteString("<plist version=\"1.0\">\n")
+	writePlistValue(&buf, root, 0)
+	buf.WriteString("</plist>\n")
+	return buf.Bytes(), nil
+}
+
+// plist를 읽어 최상위 dict를 반환한다.
+func parsePlist(data []byte) (*orderedMap, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("plist has no top-level dict")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local != "plist" {
+			value, err := readPlistValue(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			root, ok := value.(*orderedMap)
+			if !ok {
+				return nil, fmt.Errorf("top-level plist value must be a dict")
+			}
+			return root, nil
+		}
+	}
+}
+
+func readPlistValue(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	switch start.Name.Local {
+	case "dict":
+		dict := newOrderedMap()
+		key := ""
+		for {
+			token, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if key, err = readPlistText(dec); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				value, err := readPlistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				dict.Set(key, value)
+			case xml.EndElement:
+				return dict, nil
+			}
+		}
+	case "array":
+		var items []interface{}
+		for {
+			token, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				value, err := readPlistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, value)
+			case xml.EndElement:
+				return items, nil
+			}
+		}
+	case "string":
+		return readPlistText(dec)
+	default:
+		text, err := readPlistText(dec)
+		return plistRaw{tag: start.Name.Local, text: text}, err
+	}
+}
+
+// 요소의 텍스트를 끝 태그까지 읽는다.
+func readPlistText(dec *xml.Decoder) (string, error) {
+	var b strings.Builder
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.EndElement:
+			return b.String(), nil
+		case xml.StartElement:
+			return "", fmt.Errorf("unexpected <%s> in plist text", t.Name.Local)
+		}
+	}
+}
+
+// Xcode와 같은 탭 들여쓰기로 plist 값을 쓴다.
+func writePlistValue(buf *bytes.Buffer, value interface{}, depth int) {
+	indent := strings.Repeat("\t", depth)
+	switch v := value.(type) {
+	case *orderedMap:
+		buf.WriteString(indent + "<dict>\n")
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			buf.WriteString(indent + "\t<key>" + xmlText(key) + "</key>\n")
+			writePlistValue(buf, child, depth+1)
+		}
+		buf.WriteString(indent + "</dict>\n")
+	case []interface{}:
+		buf.WriteString(indent + "<array>\n")
+		for _, item := range v {
+			writePlistValue(buf, item, depth+1)
+		}
+		buf.WriteString(indent + "</array>\n")
+	case string:
+		buf.WriteString(indent + "<string>" + xmlText(v) + "</string>\n")
+	case plistRaw:
+		if v.text == "" && (v.tag == "true" || v.tag == "false") {
+			buf.WriteString(indent + "<" + v.tag + "/>\n")
+		} else {
+			buf.WriteString(indent + "<" + v.tag + ">" + xmlText(v.text) + "</" + v.tag + ">\n")
+		}
+	}
+}
+
+func xmlText(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
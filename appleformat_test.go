@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf16"
+)
+
+func TestStringsFormatRoundTrip(t *testing.T) {
+	runFormatRoundTrips(t, []formatRoundTrip{
+		{
+			name:        "comments and escapes",
+			path:        "Localizable.strings",
+			source:      "/* greeting */\n\"a\" = \"Hi\";\n\"b\" = \"Say \\\"hi\\\"\\n\";\n",
+			wantContent: `{"a":"Hi","b":"Say \"hi\"\n"}`,
+			translated:  `{"a":"Salut","b":"Dis \"salut\"\n"}`,
+			want:        "/* greeting */\n\"a\" = \"Salut\";\n\"b\" = \"Dis \\\"salut\\\"\\n\";\n",
+		},
+	})
+}
+
+func TestStringsFormatUTF16(t *testing.T) {
+	text := "\"a\" = \"Hi\";\n"
+	data := []byte{0xFF, 0xFE}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = append(data, byte(unit), byte(unit>>8))
+	}
+	doc, err := parseLocaleFile("Localizable.strings", data, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustJSON(t, doc.Content); got != `{"a":"Hi"}` {
+		t.Errorf("content = %s", got)
+	}
+	out, err := doc.render(mustParse(t, `{"a":"Salut"}`), "fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\"a\" = \"Salut\";\n"; string(out) != want {
+		t.Errorf("rendered %q, want UTF-8 %q", out, want)
+	}
+}
+
+const testStringsdictSource = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@n@ here</string>
+		<key>n</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d file</string>
+			<key>other</key>
+			<string>%d files</string>
+		</dict>
+	</dict>
+	<key>untranslated</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@n@</string>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestStringsdictFormatRoundTrip(t *testing.T) {
+	runFormatRoundTrips(t, []formatRoundTrip{
+		{
+			name:        "plural variants follow the target categories",
+			path:        "Localizable.stringsdict",
+			source:      testStringsdictSource,
+			wantContent: `{"files":{"NSStringLocalizedFormatKey":"%#@n@ here","n":{"one":"%d file","other":"%d files"}},"untranslated":{"NSStringLocalizedFormatKey":"%#@n@"}}`,
+			translated:  `{"files":{"NSStringLocalizedFormatKey":"%#@n@ ici","n":{"one":"%d fichier","many":"%d fichiers","other":"%d fichiers"}}}`,
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@n@ ici</string>
+		<key>n</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d fichier</string>
+			<key>many</key>
+			<string>%d fichiers</string>
+			<key>other</key>
+			<string>%d fichiers</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`,
+		},
+	})
+}
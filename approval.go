@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const DEFAULT_APPROVALS_FILE = ".i18n/approved.json"
+
+// 사람이 검토해 승인한 번역
+// 승인된 키는 이후 번역 실행에서 다시 요청하지 않고 대상 파일의 값을 그대로 유지한다.
+type approval struct {
+	Source     string    `json:"source"` // 승인 당시의 소스 값 (소스가 바뀌었는지 확인용)
+	ApprovedAt time.Time `json:"approvedAt"`
+}
+
+// 언어 → 파일 → 키 경로 → 승인
+type approvals struct {
+	path      string
+	Languages map[string]map[string]map[string]approval
+}
+
+// 승인 기록 로드 (파일이 없으면 빈 기록)
+func loadApprovals(path string) (*approvals, error) {
+	a := &approvals{path: path, Languages: make(map[string]map[string]map[string]approval)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading approvals: %v", err)
+	}
+	if err := json.Unmarshal(data, &a.Languages); err != nil {
+		return nil, fmt.Errorf("error parsing approvals %s: %v", path, err)
+	}
+	return a, nil
+}
+
+func (a *approvals) Save() error {
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return fmt.Errorf("error creating approvals directory: %v", err)
+	}
+	data, err := json.MarshalIndent(a.Languages, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling approvals: %v", err)
+	}
+	return os.WriteFile(a.path, data, 0644)
+}
+
+// 파일의 승인된 키 (기록이 없으면 nil)
+func (a *approvals) forFile(lang, file string) map[string]approval {
+	if a == nil {
+		return nil
+	}
+	return a.Languages[lang][file]
+}
+
+func (a *approvals) approve(lang, file, path, source string) {
+	files := a.Languages[lang]
+	if files == nil {
+		files = make(map[string]map[string]approval)
+		a.Languages[lang] = files
+	}
+	keys := files[file]
+	if keys == nil {
+		keys = make(map[string]approval)
+		files[file] = keys
+	}
+	keys[path] = approval{Source: source, ApprovedAt: time.Now().UTC()}
+}
+
+// 작업에서 번역하지 않고 유지할 승인된 값 (대상 파일에 값이 남아 있는 키만)
+func approvedValues(job TranslationJob, opts *translateOptions) (map[string]interface{}, error) {
+	approved := opts.approvals.forFile(job.TargetLang, job.File)
+	if len(approved) == 0 {
+		return nil, nil
+	}
+	existing, err := readLocaleTree(targetFilePath(opts.OutDir, job.TargetLang, job.File), job.TargetLang)
+	if err != nil {
+		return nil, fmt.Errorf("error reading existing translation: %v", err)
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	sourceLeaves := flattenTree(job.Content)
+	existingLeaves := flattenTree(existing)
+	values := make(map[string]interface{})
+	for path := range approved {
+		if _, ok := sourceLeaves[path].(string); !ok {
+			continue
+		}
+		if value, ok := existingLeaves[path]; ok {
+			values[path] = value
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// 지출 한도 (0이면 제한 없음)
+// 토큰은 프롬프트와 출력 토큰의 합, 비용은 가격표(pricing)로 계산한 USD다.
+type budgetLimits struct {
+	MaxTokens int64   `yaml:"maxTokens"`
+	MaxCost   float64 `yaml:"maxCost"`
+}
+
+func (l budgetLimits) enabled() bool {
+	return l.MaxTokens > 0 || l.MaxCost > 0
+}
+
+// 지출이 한도를 넘었으면 이유를 반환한다.
+func (l budgetLimits) exceededBy(s spend) string {
+	if l.MaxTokens > 0 && s.Tokens > l.MaxTokens {
+		return fmt.Sprintf("%d tokens exceeds the limit of %d", s.Tokens, l.MaxTokens)
+	}
+	if l.MaxCost > 0 && s.Cost > l.MaxCost {
+		return fmt.Sprintf("$%.4f exceeds the limit of $%g", s.Cost, l.MaxCost)
+	}
+	return ""
+}
+
+type spend struct {
+	Tokens int64
+	Cost   float64
+}
+
+func (s spend) plus(other spend) spend {
+	return spend{Tokens: s.Tokens + other.Tokens, Cost: s.Cost + other.Cost}
+}
+
+func (s spend) minus(other spend) spend {
+	return spend{Tokens: s.Tokens - other.Tokens, Cost: s.Cost - other.Cost}
+}
+
+// 한도를 넘어 작업을 시작하지 않았거나 취소한 이유 (Lang이 비어 있으면 실행 전체)
+type budgetExceededError struct {
+	Lang   string
+	Reason string
+}
+
+func (e *budgetExceededError) Error() string {
+	if e.Lang == "" {
+		return "run budget exceeded: " + e.Reason
+	}
+	return fmt.Sprintf("budget for %s exceeded: %s", e.Lang, e.Reason)
+}
+
+// 실행 전체와 언어별 지출을 추적한다.
+// 작업을 시작하기 전에는 지금까지의 지출, 진행 중인 작업의 남은 예상 지출, 새 작업의 예상 지출을 더해
+// 한도를 넘으면 시작하지 않고, 요청마다 실제 지출을 더해 한도를 넘으면 실행(또는 그 언어)의 진행 중인 요청을 취소한다.
+type spendBudget struct {
+	run, perLanguage budgetLimits
+
+	mu        sync.Mutex
+	total     spendAccount
+	languages map[string]*spendAccount
+}
+
+type spendAccount struct {
+	spent    spend // 실제 지출
+	reserved spend // 진행 중인 작업의 남은 예상 지출
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+}
+
+// 한도가 없으면 nil을 반환한다. 반환한 컨텍스트는 실행 한도를 넘으면 취소된다.
+func newSpendBudget(ctx context.Context, run, perLanguage budgetLimits) (*spendBudget, context.Context) {
+	if !run.enabled() && !perLanguage.enabled() {
+		return nil, ctx
+	}
+	b := &spendBudget{run: run, perLanguage: perLanguage, languages: make(map[string]*spendAccount)}
+	b.total.ctx, b.total.cancel = context.WithCancelCause(ctx)
+	return b, b.total.ctx
+}
+
+func (b *spendBudget) account(lang string) *spendAccount {
+	account, ok := b.languages[lang]
+	if !ok {
+		account = &spendAccount{}
+		account.ctx, account.cancel = context.WithCancelCause(b.total.ctx)
+		b.languages[lang] = account
+	}
+	return account
+}
+
+// 언어의 작업에 사용할 컨텍스트 (언어 한도를 넘으면 취소된다)
+func (b *spendBudget) languageContext(ctx context.Context, lang string) context.Context {
+	if b == nil {
+		return ctx
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.account(lang).ctx
+}
+
+// 예상 지출로 작업을 시작할 수 있는지 확인하고 예약한다. 한도를 넘으면 *budgetExceededError를 반환한다.
+func (b *spendBudget) start(lang string, projected costEstimate, price ModelPrice) (*budgetJob, error) {
+	if b == nil {
+		return nil, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	account := b.account(lang)
+	if err := context.Cause(account.ctx); err != nil {
+		if exceeded, ok := err.(*budgetExceededError); ok {
+			return nil, exceeded
+		}
+	}
+
+	reserve := spend{Tokens: projected.PromptTokens + projected.OutputTokens, Cost: projected.Cost}
+	if reason := b.run.exceededBy(b.total.spent.plus(b.total.reserved).plus(reserve)); reason != "" {
+		return nil, &budgetExceededError{Reason: "projected " + reason}
+	}
+	if reason := b.perLanguage.exceededBy(account.spent.plus(account.reserved).plus(reserve)); reason != "" {
+		return nil, &budgetExceededError{Lang: lang, Reason: "projected " + reason}
+	}
+
+	b.total.reserved = b.total.reserved.plus(reserve)
+	account.reserved = account.reserved.plus(reserve)
+	return &budgetJob{budget: b, lang: lang, price: price, reserved: reserve}, nil
+}
+
+// 시작한 작업의 지출 (예약한 예상 지출을 실제 지출로 바꿔 가며 기록한다)
+type budgetJob struct {
+	budget   *spendBudget
+	lang     string
+	price    ModelPrice
+	reserved spend // 아직 실제 지출로 바뀌지 않은 예약
+}
+
+// 요청 하나의 실제 사용량을 기록한다. 한도를 넘으면 실행 또는 언어의 컨텍스트를 취소한다.
+func (j *budgetJob) charge(promptTokens, completionTokens, characters int64) {
+	if j == nil {
+		return
+	}
+	b := j.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	used := spend{Tokens: promptTokens + completionTokens, Cost: j.price.cost(promptTokens, completionTokens, characters)}
+	released := spend{Tokens: min(used.Tokens, j.reserved.Tokens), Cost: min(used.Cost, j.reserved.Cost)}
+	j.reserved = j.reserved.minus(released)
+
+	account := b.account(j.lang)
+	for _, a := range []*spendAccount{&b.total, account} {
+		a.spent = a.spent.plus(used)
+		a.reserved = a.reserved.minus(released)
+	}
+
+	if reason := b.run.exceededBy(b.total.spent); reason != "" {
+		b.total.cancel(&budgetExceededError{Reason: reason})
+	}
+	if reason := b.perLanguage.exceededBy(account.spent); reason != "" {
+		account.cancel(&budgetExceededError{Lang: j.lang, Reason: reason})
+	}
+}
+
+// 작업이 끝나면 남은 예약을 푼다.
+func (j *budgetJob) finish() {
+	if j == nil {
+		return
+	}
+	b := j.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.total.reserved = b.total.reserved.minus(j.reserved)
+	account := b.account(j.lang)
+	account.reserved = account.reserved.minus(j.reserved)
+	j.reserved = spend{}
+}
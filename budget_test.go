@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// 100만 토큰당 $1
+var testPrice = ModelPrice{Input: 1, Output: 1}
+
+func projectedTokens(n int64) costEstimate {
+	return costEstimate{PromptTokens: n, Cost: testPrice.cost(n, 0, 0)}
+}
+
+func TestSpendBudgetStart(t *testing.T) {
+	tests := []struct {
+		name        string
+		run, lang   budgetLimits
+		started     map[string]int64 // 이미 시작한 작업의 언어별 예상 토큰
+		finished    bool             // 시작한 작업이 지출 없이 끝났는지
+		projected   int64
+		wantErr     bool
+		wantErrLang string
+	}{
+		{name: "within the run limit", run: budgetLimits{MaxTokens: 100}, started: map[string]int64{"de": 40}, projected: 60},
+		{name: "reservations count against the run limit", run: budgetLimits{MaxTokens: 100}, started: map[string]int64{"de": 40}, projected: 61, wantErr: true},
+		{name: "finished jobs release their reservation", run: budgetLimits{MaxTokens: 100}, started: map[string]int64{"de": 40}, finished: true, projected: 100},
+		{name: "language limit only counts that language", lang: budgetLimits{MaxTokens: 50}, started: map[string]int64{"de": 50}, projected: 50},
+		{name: "language limit", lang: budgetLimits{MaxTokens: 50}, started: map[string]int64{"fr": 30}, projected: 21, wantErr: true, wantErrLang: "fr"},
+		{name: "cost limit", run: budgetLimits{MaxCost: 0.0001}, projected: 101, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, _ := newSpendBudget(context.Background(), tt.run, tt.lang)
+			for lang, n := range tt.started {
+				job, err := budget.start(lang, projectedTokens(n), testPrice)
+				if err != nil {
+					t.Fatalf("start %s: %v", lang, err)
+				}
+				if tt.finished {
+					job.finish()
+				}
+			}
+
+			_, err := budget.start("fr", projectedTokens(tt.projected), testPrice)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("start error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				exceeded, ok := err.(*budgetExceededError)
+				if !ok {
+					t.Fatalf("error %T, want *budgetExceededError", err)
+				}
+				if exceeded.Lang != tt.wantErrLang {
+					t.Errorf("exceeded language = %q, want %q", exceeded.Lang, tt.wantErrLang)
+				}
+			}
+		})
+	}
+}
+
+func TestSpendBudgetCharge(t *testing.T) {
+	tests := []struct {
+		name          string
+		run, lang     budgetLimits
+		charged       int64 // fr 작업의 실제 사용 토큰
+		wantRunCancel bool
+		wantFrCancel  bool
+	}{
+		{name: "within limits", run: budgetLimits{MaxTokens: 100}, lang: budgetLimits{MaxTokens: 80}, charged: 80},
+		{name: "language limit cancels only that language", lang: budgetLimits{MaxTokens: 80}, charged: 81, wantFrCancel: true},
+		{name: "run limit cancels every language", run: budgetLimits{MaxTokens: 100}, charged: 101, wantRunCancel: true, wantFrCancel: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, runCtx := newSpendBudget(context.Background(), tt.run, tt.lang)
+			frCtx := budget.languageContext(runCtx, "fr")
+			deCtx := budget.languageContext(runCtx, "de")
+
+			job, err := budget.start("fr", projectedTokens(10), testPrice)
+			if err != nil {
+				t.Fatal(err)
+			}
+			job.charge(tt.charged/2, tt.charged-tt.charged/2, 0)
+			job.finish()
+
+			if got := runCtx.Err() != nil; got != tt.wantRunCancel {
+				t.Errorf("run cancelled = %v, want %v", got, tt.wantRunCancel)
+			}
+			if got := frCtx.Err() != nil; got != tt.wantFrCancel {
+				t.Errorf("fr cancelled = %v, want %v", got, tt.wantFrCancel)
+			}
+			if got := deCtx.Err() != nil; got != tt.wantRunCancel {
+				t.Errorf("de cancelled = %v, want %v", got, tt.wantRunCancel)
+			}
+			if tt.wantFrCancel {
+				if _, ok := context.Cause(frCtx).(*budgetExceededError); !ok {
+					t.Errorf("cancel cause = %v, want *budgetExceededError", context.Cause(frCtx))
+				}
+				// 한도를 넘은 언어의 새 작업은 시작하지 않는다
+				if _, err := budget.start("fr", projectedTokens(1), testPrice); err == nil {
+					t.Error("started a job after the budget was exceeded")
+				}
+			}
+		})
+	}
+}
+
+func TestSpendBudgetDisabled(t *testing.T) {
+	ctx := context.Background()
+	budget, got := newSpendBudget(ctx, budgetLimits{}, budgetLimits{})
+	if budget != nil || got != ctx {
+		t.Fatal("budget without limits is not nil")
+	}
+	job, err := budget.start("fr", projectedTokens(1e9), testPrice)
+	if err != nil || job != nil {
+		t.Fatalf("start on a nil budget = %v, %v", job, err)
+	}
+	job.charge(1e9, 1e9, 0)
+	job.finish()
+}
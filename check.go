@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 모델을 호출하지 않고 소스와 대상 파일을 비교하는 검사 (CI용)
+
+// 검사에서 찾은 문제
+type checkProblem struct {
+	Lang   string
+	File   string
+	Path   string
+	Kind   string // "missing", "stale", "extra", "type", "mismatch"
+	Detail string
+}
+
+func (p checkProblem) String() string {
+	line := fmt.Sprintf("%s/%s: %s", p.Lang, p.File, p.Kind)
+	if p.Path != "" {
+		line += " " + p.Path
+	}
+	if p.Detail != "" {
+		line += " (" + p.Detail + ")"
+	}
+	return line
+}
+
+// 검사할 대상 언어: --to/--all-from-languageMap이나 설정 파일의 targets가 있으면 그 언어 전체,
+// 없으면 languageMap의 언어 중 출력 디렉터리에 하위 디렉터리가 있는 언어
+func checkLanguages(opts *translateOptions) []string {
+	if opts.AllLanguages || len(opts.Targets) > 0 {
+		return resolveTargetLanguages(opts)
+	}
+	excluded := map[string]bool{opts.SourceLang: true}
+	for _, lang := range opts.Exclude {
+		excluded[lang] = true
+	}
+	var languages []string
+	for lang := range languageMap {
+		if excluded[lang] {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(opts.OutDir, lang)); err == nil && info.IsDir() {
+			languages = append(languages, lang)
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// 소스 파일과 언어별 대상 파일을 비교한다.
+func checkTranslations(opts *translateOptions, languages []string) ([]checkProblem, error) {
+	sourceFiles, err := discoverSourceFiles(opts.Source, opts.SourceLang, opts.Include, opts.ExcludeFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []checkProblem
+	for _, lang := range languages {
+		for _, file := range sourceFiles {
+			job := prepareJob(TranslationJob{
+				SourceLang: opts.SourceLang,
+				TargetLang: lang,
+				File:       file.RelPath,
+				Content:    file.Content,
+				Context:    file.Context,
+				Document:   file.Document,
+			}, opts)
+			fileProblems, err := checkJob(job, opts)
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, fileProblems...)
+		}
+	}
+	return problems, nil
+}
+
+func checkJob(job TranslationJob, opts *translateOptions) ([]checkProblem, error) {
+	problem := func(path, kind, detail string) checkProblem {
+		return checkProblem{Lang: job.TargetLang, File: job.File, Path: path, Kind: kind, Detail: detail}
+	}
+
+	existing, err := readLocaleTree(targetFilePath(opts.OutDir, job.TargetLang, job.File), job.TargetLang)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s translation of %s: %v", job.TargetLang, job.File, err)
+	}
+	if existing == nil {
+		return []checkProblem{problem("", "missing", "no translated file")}, nil
+	}
+	snapshot, err := loadSnapshot(snapshotPath(opts.SnapshotDir, job.TargetLang, job.File))
+	if err != nil {
+		return nil, err
+	}
+	approved := opts.approvals.forFile(job.TargetLang, job.File)
+
+	var problems []checkProblem
+	for _, issue := range validateStructure(job.Content, existing) {
+		switch issue.Kind {
+		case "missing", "extra":
+			problems = append(problems, problem(issue.Path, issue.Kind, ""))
+		default:
+			problems = append(problems, problem(issue.Path, "type", fmt.Sprintf("expected %s, got %s", issue.Expected, issue.Actual)))
+		}
+	}
+
+	existingLeaves := flattenTree(existing)
+	walkLeaves(job.Content, "", func(path string, value interface{}) {
+		source, ok := value.(string)
+		if !ok {
+			return
+		}
+		translated, ok := existingLeaves[path].(string)
+		if !ok {
+			return
+		}
+		if entry, ok := approved[path]; ok {
+			if entry.Source != source {
+				problems = append(problems, problem(path, "stale", "source changed since the translation was approved"))
+			}
+		} else if previous, ok := snapshot[path]; ok && previous != source {
+			problems = append(problems, problem(path, "stale", "source changed since the last translation"))
+		}
+		if issue := checkTranslatedValue(path, source, translated, job.TargetLang, opts.glossary, job.Context[path].MaxLength); issue != nil {
+			problems = append(problems, problem(path, "mismatch", strings.TrimPrefix(issue.String(), path+": ")))
+		}
+	})
+	return problems, nil
+}
+
+// 문제 종류별 개수 요약 (예: "3 missing, 1 stale")
+func summarizeProblems(problems []checkProblem) string {
+	counts := make(map[string]int)
+	for _, p := range problems {
+		counts[p.Kind]++
+	}
+	var parts []string
+	for _, kind := range []string{"missing", "stale", "extra", "type", "mismatch"} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		target   string            // 비어 있으면 대상 파일 없음
+		snapshot string            // 마지막 번역 때의 소스
+		approved map[string]string // 경로 → 승인 당시 소스
+		want     []string
+	}{
+		{
+			name:   "up to date",
+			source: `{"a":"Hello {name}","n":{"b":"Bye"},"count":2}`,
+			target: `{"a":"Bonjour {name}","n":{"b":"Salut"},"count":2}`,
+		},
+		{
+			name:   "no translated file",
+			source: `{"a":"Hello"}`,
+			want:   []string{"fr/common.json: missing (no translated file)"},
+		},
+		{
+			name:   "missing and extra keys",
+			source: `{"a":"A","n":{"b":"B","c":"C"},"list":["x","y"]}`,
+			target: `{"a":"a","n":{"b":"b","old":"o"},"list":["x"]}`,
+			want:   []string{"fr/common.json: missing /n/c", "fr/common.json: extra /n/old", "fr/common.json: missing /list/1"},
+		},
+		{
+			name:   "changed type",
+			source: `{"a":"A","n":{"b":"B"}}`,
+			target: `{"a":["a"],"n":{"b":"b"}}`,
+			want:   []string{"fr/common.json: type /a (expected string, got array)"},
+		},
+		{
+			name:     "stale since the last translation",
+			source:   `{"a":"Hello again","b":"Bye"}`,
+			target:   `{"a":"Bonjour","b":"Salut"}`,
+			snapshot: `{"/a":"Hello","/b":"Bye"}`,
+			want:     []string{"fr/common.json: stale /a (source changed since the last translation)"},
+		},
+		{
+			name:     "stale since approval wins over the snapshot",
+			source:   `{"a":"Hello again","b":"Bye"}`,
+			target:   `{"a":"Bonjour","b":"Salut"}`,
+			snapshot: `{"/a":"Hello again","/b":"Bye"}`,
+			approved: map[string]string{"/a": "Hello", "/b": "Bye"},
+			want:     []string{"fr/common.json: stale /a (source changed since the translation was approved)"},
+		},
+		{
+			name:   "placeholder mismatch",
+			source: `{"a":"Hello {name}","b":"{count} items","c":"<b>Bold</b>"}`,
+			target: `{"a":"Bonjour {nom}","b":"{count} articles","c":"Gras"}`,
+			want: []string{
+				"fr/common.json: mismatch /a (missing placeholder {name}, unexpected placeholder {nom})",
+				"fr/common.json: mismatch /c (missing tag </b>, missing tag <b>)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.target != "" {
+				writeTestFile(t, filepath.Join(dir, "locales", "fr", "common.json"), tt.target)
+			}
+			if tt.snapshot != "" {
+				writeTestFile(t, filepath.Join(dir, "snapshots", "fr", "common.json"), tt.snapshot)
+			}
+			approvals, err := loadApprovals(filepath.Join(dir, "approved.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for path, source := range tt.approved {
+				approvals.approve("fr", "common.json", path, source)
+			}
+			opts := &translateOptions{OutDir: filepath.Join(dir, "locales"), SnapshotDir: filepath.Join(dir, "snapshots"), approvals: approvals}
+			job := TranslationJob{SourceLang: "en", TargetLang: "fr", File: "common.json", Content: mustParse(t, tt.source)}
+
+			problems, err := checkJob(job, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range problems {
+				got = append(got, p.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeProblems(t *testing.T) {
+	problems := []checkProblem{{Kind: "mismatch"}, {Kind: "missing"}, {Kind: "stale"}, {Kind: "missing"}}
+	if got, want := summarizeProblems(problems), "2 missing, 1 stale, 1 mismatch"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	DEFAULT_CHUNK_TOKENS  = 1500 // 청크당 소스 토큰 예산 기본값
+	MAX_CONCURRENT_CHUNKS = 4    // 작업 하나에서 동시에 번역할 최대 청크 수 기본값
+)
+
+// 텍스트의 대략적인 토큰 수 (영문 기준 약 4글자당 1토큰)
+func estimateTokens(text string) int {
+	return utf8.RuneCountInString(text)/4 + 1
+}
+
+// 번역할 문자열 리프를 원래 키 순서대로 토큰 예산 단위의 청크로 나눈다.
+// 예산보다 큰 값 하나는 단독 청크가 된다.
+func splitIntoChunks(content interface{}, budget int) [][]string {
+	var chunks [][]string
+	var current []string
+	currentTokens := 0
+
+	walkLeaves(content, "", func(path string, value interface{}) {
+		text, ok := value.(string)
+		if !ok {
+			return // 문자열이 아닌 값은 번역하지 않음
+		}
+
+		tokens := estimateTokens(path) + estimateTokens(text)
+		if len(current) > 0 && currentTokens+tokens > budget {
+			chunks = append(chunks, current)
+			current = nil
+			currentTokens = 0
+		}
+		current = append(current, path)
+		currentTokens += tokens
+	})
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// 콘텐츠를 키 단위 청크로 나누어 동시에 번역한 뒤 원래 중첩 구조와 키 순서대로 다시 조립한다.
+// 문자열이 아닌 값은 소스 값을 그대로 사용한다.
+func translateChunked(ctx context.Context, translator Translator, content interface{}, sourceLang, targetLang string, notes contextNotes, opts *translateOptions, stats *requestStats) (interface{}, []placeholderIssue, error) {
+	budget := opts.chunkBudget()
+
+	original := content
+	sourceLeaves := flattenTree(content)
+
+	// 번역 메모리에 있는 값은 요청하지 않는다
+	model := translator.Name()
+	memoryHash := memoryHasher(translator, notes)
+	translatedLeaves := lookupMemory(translator, sourceLeaves, sourceLang, targetLang, notes, opts, false)
+	if len(translatedLeaves) > 0 {
+		stats.memoryHits.Add(int64(len(translatedLeaves)))
+		log.Printf("%s: 번역 메모리에서 %d개 키 재사용", targetLang, len(translatedLeaves))
+		content = withoutLeaves(content, translatedLeaves)
+	}
+
+	chunks := splitIntoChunks(content, budget)
+	if len(chunks) > 1 {
+		log.Printf("%s: %d개 키를 %d개 청크로 분할", targetLang, len(sourceLeaves)-len(translatedLeaves), len(chunks))
+	}
+
+	var placeholderIssues []placeholderIssue
+	var mu sync.Mutex
+	var firstErr error
+
+	// 한 청크가 실패하면 나머지 청크 요청도 취소
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, opts.ChunkJobs)
+	var wg sync.WaitGroup
+
+	for i, paths := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(index int, paths []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			chunkContent := chunkTree(content, sourceLeaves, paths)
+			translated, issues, err := translateChunk(ctx, translator, chunkContent, sourceLang, targetLang, notes, opts, stats)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("chunk %d/%d: %w", index+1, len(chunks), err)
+					cancel()
+				}
+				return
+			}
+			for path, value := range translated {
+				translatedLeaves[path] = value
+			}
+			placeholderIssues = append(placeholderIssues, issues...)
+
+			// 검증을 통과한 번역만 번역 메모리에 저장 (플레이스홀더 불일치로 보고된 값 제외)
+			if opts.memory != nil {
+				flagged := make(map[string]bool, len(issues))
+				for _, issue := range issues {
+					flagged[issue.Path] = true
+				}
+				for path, value := range translated {
+					source, _ := sourceLeaves[path].(string)
+					text, ok := value.(string)
+					if !ok || flagged[path] {
+						continue
+					}
+					if err := opts.memory.Store(source, text, sourceLang, targetLang, model, memoryHash(path)); err != nil {
+						log.Printf("Warning: could not store translation memory entry for %s: %v", path, err)
+					}
+				}
+			}
+		}(i, paths)
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(placeholderIssues, func(i, j int) bool { return placeholderIssues[i].Path < placeholderIssues[j].Path })
+
+	result, _ := rebuildTree(original, "", func(path string) (interface{}, bool) {
+		value := sourceLeaves[path]
+		if _, ok := value.(string); !ok {
+			return value, true
+		}
+		translated, ok := translatedLeaves[path]
+		return translated, ok
+	})
+	return result, placeholderIssues, nil
+}
+
+// 청크당 소스 토큰 예산
+func (opts *translateOptions) chunkBudget() int {
+	if opts.ChunkTokens <= 0 {
+		return DEFAULT_CHUNK_TOKENS
+	}
+	return opts.ChunkTokens
+}
+
+// 번역 메모리 키의 프롬프트 해시
+// 맥락이 있는 키는 같은 원문이라도 다른 번역일 수 있으므로 맥락을 프롬프트 해시에 더한다.
+func memoryHasher(translator Translator, notes contextNotes) func(path string) string {
+	promptHash := translator.PromptHash()
+	return func(path string) string {
+		if note, ok := notes[path]; ok {
+			return hashBytes([]byte(promptHash + "\x00" + note.note()))
+		}
+		return promptHash
+	}
+}
+
+// 번역 메모리에서 재사용할 수 있는 번역 (메모리를 사용하지 않으면 빈 맵, peek이면 사용 기록을 바꾸지 않는다)
+func lookupMemory(translator Translator, sourceLeaves map[string]interface{}, sourceLang, targetLang string, notes contextNotes, opts *translateOptions, peek bool) map[string]interface{} {
+	found := make(map[string]interface{})
+	if opts.memory == nil {
+		return found
+	}
+	model, memoryHash := translator.Name(), memoryHasher(translator, notes)
+	lookup := opts.memory.Lookup
+	if peek {
+		lookup = opts.memory.Peek
+	}
+	for path, value := range sourceLeaves {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		// 용어집이나 길이 제한이 바뀌어 더 이상 맞지 않는 번역은 다시 요청
+		if translated, ok := lookup(text, sourceLang, targetLang, model, memoryHash(path)); ok && checkTranslatedValue(path, text, translated, targetLang, opts.glossary, notes[path].MaxLength) == nil {
+			found[path] = translated
+		}
+	}
+	return found
+}
+
+// leaves에 있는 키를 뺀 트리 (배열 원소의 경로는 그대로 유지)
+func withoutLeaves(content interface{}, leaves map[string]interface{}) interface{} {
+	sourceLeaves := flattenTree(content)
+	result, _ := subTree(content, "", func(path string) (interface{}, bool) {
+		if _, ok := leaves[path]; ok {
+			return nil, false
+		}
+		return sourceLeaves[path], true
+	})
+	return result
+}
+
+// 청크에 포함된 키만 남긴 부분 트리
+func chunkTree(content interface{}, sourceLeaves map[string]interface{}, paths []string) interface{} {
+	inChunk := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		inChunk[path] = true
+	}
+	result, _ := subTree(content, "", func(path string) (interface{}, bool) {
+		if !inChunk[path] {
+			return nil, false
+		}
+		return sourceLeaves[path], true
+	})
+	return result
+}
+
+// 번역 요청
+// 오류를 분류해 영구 오류는 바로 실패시키고, 나머지는 opts.MaxRetries까지 지수 백오프로 재시도한다.
+func translateWithRetry(ctx context.Context, translator Translator, content interface{}, sourceLang, targetLang string, opts *translateOptions, stats *requestStats) (interface{}, error) {
+	policy := opts.retryPolicy()
+	ctx = withRequestStats(ctx, stats)
+
+	for attempt := 1; ; attempt++ {
+		stats.requests.Add(1)
+		translated, err := translateOnce(ctx, translator, content, sourceLang, targetLang, opts.RequestTimeout)
+		if err == nil {
+			return translated, nil
+		}
+		// 실행 전체가 취소되었거나 전체 시간 제한을 넘긴 경우 재시도하지 않는다
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		class, retryAfter := classifyError(err)
+		if class == errorPermanent || attempt > policy.MaxRetries {
+			return nil, fmt.Errorf("%s error after %d attempt(s): %w", class, attempt, err)
+		}
+
+		delay := policy.backoff(attempt, class, retryAfter)
+		log.Printf("Translation retry %d/%d for %s via %s in %v (%s error): %v", attempt, policy.MaxRetries, targetLang, translator.Name(), delay.Round(time.Millisecond), class, err)
+		stats.retries.Add(1)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// 속도 제한 차례를 기다린 뒤 요청별 시간 제한을 적용해 한 번 번역한다.
+func translateOnce(ctx context.Context, translator Translator, content interface{}, sourceLang, targetLang string, timeout time.Duration) (interface{}, error) {
+	// 분당 요청/토큰 한도 대기는 요청 시간 제한에 포함하지 않는다
+	ctx, err := waitForRateLimit(ctx, translator, content, sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return translator.Translate(ctx, content, sourceLang, targetLang)
+}
+
+// 청크 하나를 번역하고 소스와 구조, 플레이스홀더를 비교한다.
+// 누락되거나 타입이 바뀐 키, 플레이스홀더/태그가 깨지거나 용어집을 어긴 값은 그 키만 다시 요청한다.
+// 길이 제한을 넘은 값은 이전 번역과 길이를 맥락으로 알려 더 짧은 표현을 요청한다.
+// opts.RepairAttempts 이후에도 구조가 깨져 있으면 경로와 함께 실패시키고,
+// 플레이스홀더/용어집 불일치와 길이 초과는 마지막 번역을 사용하되 문제 목록으로 보고한다.
+func translateChunk(ctx context.Context, translator Translator, chunkContent interface{}, sourceLang, targetLang string, notes contextNotes, opts *translateOptions, stats *requestStats) (map[string]interface{}, []placeholderIssue, error) {
+	sourceLeaves := flattenTree(chunkContent)
+	accepted := make(map[string]interface{})
+	flagged := make(map[string]interface{})
+	flaggedIssues := make(map[string]placeholderIssue)
+	request := chunkContent
+
+	for attempt := 0; ; attempt++ {
+		translated, err := translateWithRetry(ctx, translator, request, sourceLang, targetLang, opts, stats)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		issues := validateStructure(request, translated)
+		for path, value := range acceptedLeaves(sourceLeaves, translated) {
+			source, _ := sourceLeaves[path].(string)
+			text, _ := value.(string)
+			if issue := checkTranslatedValue(path, source, text, targetLang, opts.glossary, notes[path].MaxLength); issue != nil {
+				flagged[path] = value
+				flaggedIssues[path] = *issue
+				continue
+			}
+			delete(flagged, path)
+			delete(flaggedIssues, path)
+			accepted[path] = value
+		}
+
+		missing := len(sourceLeaves) - len(accepted) - len(flagged)
+		if missing == 0 && len(flagged) == 0 {
+			if len(issues) > 0 {
+				// 추가된 키만 있었던 경우: 버리면 된다
+				log.Printf("%s: 번역 결과에서 소스에 없는 키를 제거했습니다: %v", targetLang, structureError(issues))
+			}
+			return accepted, nil, nil
+		}
+		if attempt >= opts.RepairAttempts {
+			if missing > 0 {
+				return nil, nil, structureError(issues)
+			}
+
+			var remaining []placeholderIssue
+			for path, value := range flagged {
+				accepted[path] = value
+				remaining = append(remaining, flaggedIssues[path])
+			}
+			sort.Slice(remaining, func(i, j int) bool { return remaining[i].Path < remaining[j].Path })
+			return accepted, remaining, nil
+		}
+
+		// 아직 확정되지 않은 키만 남긴 부분 트리로 다시 요청
+		log.Printf("%s: 구조 불일치 %d건, 누락 키 %d개, 플레이스홀더 불일치 %d개 재요청 (%d/%d)", targetLang, len(issues), missing, len(flagged), attempt+1, opts.RepairAttempts)
+		broken, _ := subTree(chunkContent, "", func(path string) (interface{}, bool) {
+			if _, ok := accepted[path]; ok {
+				return nil, false
+			}
+			return sourceLeaves[path], true
+		})
+		request = broken
+
+		overflows := make(map[string]placeholderIssue)
+		for path, issue := range flaggedIssues {
+			if issue.overflow() {
+				overflows[path] = issue
+			}
+		}
+		translator = translator.WithContext(shorterVariantNotes(notes, overflows))
+	}
+}
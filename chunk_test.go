@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+// 문자열을 "T(...)"로 바꿔 돌려주는 가짜 백엔드 (drop에 있는 경로는 첫 응답에서 빠뜨린다)
+type fakeTranslator struct {
+	mu       sync.Mutex
+	requests []string
+	drop     map[string]bool
+}
+
+func (f *fakeTranslator) Name() string                          { return "fake" }
+func (f *fakeTranslator) PromptHash() string                    { return "" }
+func (f *fakeTranslator) WithPrompt(*promptTemplate) Translator { return f }
+func (f *fakeTranslator) WithContext(contextNotes) Translator   { return f }
+
+func (f *fakeTranslator) Translate(ctx context.Context, content interface{}, sourceLang, targetLang string) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	translated := fakeTranslate(content)
+	if len(f.drop) > 0 {
+		leaves := flattenTree(translated)
+		translated, _ = subTree(translated, "", func(path string) (interface{}, bool) {
+			return leaves[path], !f.drop[path]
+		})
+		f.drop = nil
+	}
+	request, _ := json.Marshal(content)
+	f.requests = append(f.requests, string(request))
+	return translated, nil
+}
+
+func testTranslateOptions(chunkTokens int) *translateOptions {
+	return &translateOptions{ChunkTokens: chunkTokens, ChunkJobs: 2, RepairAttempts: DEFAULT_REPAIR_ATTEMPTS}
+}
+
+func TestTranslateChunkedArrays(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		chunkTokens  int
+		drop         []string
+		wantRequests int
+		want         string
+	}{
+		{
+			name:         "array with a non-string element in one chunk",
+			source:       `{"steps":[1,"Go","Stop"],"x":"Hi"}`,
+			chunkTokens:  1000,
+			wantRequests: 1,
+			want:         `{"steps":[1,"T(Go)","T(Stop)"],"x":"T(Hi)"}`,
+		},
+		{
+			name:         "array split across chunks",
+			source:       `{"a":["aaaa aaaa aaaa aaaa","bbbb bbbb bbbb bbbb","cccc cccc cccc cccc"]}`,
+			chunkTokens:  10,
+			wantRequests: 3,
+			want:         `{"a":["T(aaaa aaaa aaaa aaaa)","T(bbbb bbbb bbbb bbbb)","T(cccc cccc cccc cccc)"]}`,
+		},
+		{
+			name:         "nested objects in an array split across chunks",
+			source:       `{"list":[{"t":"first item here"},{"t":"second item here"}],"z":"last one"}`,
+			chunkTokens:  12,
+			wantRequests: 2,
+			want:         `{"list":[{"t":"T(first item here)"},{"t":"T(second item here)"}],"z":"T(last one)"}`,
+		},
+		{
+			name:         "missing array element is re-requested at its own index",
+			source:       `{"steps":["Open","Go","Stop"]}`,
+			chunkTokens:  1000,
+			drop:         []string{"/steps/1"},
+			wantRequests: 2,
+			want:         `{"steps":["T(Open)","T(Go)","T(Stop)"]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translator := &fakeTranslator{drop: make(map[string]bool)}
+			for _, path := range tt.drop {
+				translator.drop[path] = true
+			}
+			result, issues, err := translateChunked(context.Background(), translator, mustParse(t, tt.source), "en", "pl", nil, testTranslateOptions(tt.chunkTokens), &requestStats{})
+			if err != nil {
+				t.Fatalf("translateChunked: %v", err)
+			}
+			if len(issues) > 0 {
+				t.Errorf("unexpected issues: %v", issues)
+			}
+			if got := mustJSON(t, result); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if len(translator.requests) != tt.wantRequests {
+				t.Errorf("%d requests, want %d: %v", len(translator.requests), tt.wantRequests, translator.requests)
+			}
+		})
+	}
+}
+
+func TestSplitIntoChunksCoversEveryString(t *testing.T) {
+	source := mustParse(t, `{"a":"one two three","b":[1,"four five six","seven eight"],"c":{"d":"nine ten"},"e":true}`)
+	for _, budget := range []int{1, 5, 10, 1000} {
+		seen := make(map[string]bool)
+		for _, paths := range splitIntoChunks(source, budget) {
+			chunk := flattenTree(chunkTree(source, flattenTree(source), paths))
+			if len(chunk) != len(paths) {
+				t.Errorf("budget %d: chunk %v has leaves %v", budget, paths, chunk)
+			}
+			for _, path := range paths {
+				if seen[path] {
+					t.Errorf("budget %d: %s in more than one chunk", budget, path)
+				}
+				seen[path] = true
+			}
+		}
+		for _, path := range []string{"/a", "/b/1", "/b/2", "/c/d"} {
+			if !seen[path] {
+				t.Errorf("budget %d: %s not in any chunk", budget, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// translate 서브커맨드 옵션
+type translateOptions struct {
+	Source         string
+	SourceLang     string
+	Include        []string // 소스 디렉터리 기준 파일 glob
+	ExcludeFiles   []string
+	Targets        []string
+	AllLanguages   bool
+	Exclude        []string
+	OutDir         string
+	Incremental    bool
+	SnapshotDir    string
+	StateFile      string
+	Resume         bool
+	Jobs           int
+	ChunkJobs      int
+	ChunkTokens    int
+	MaxRetries     int
+	RetryDelay     time.Duration
+	MaxRetryDelay  time.Duration
+	RepairAttempts int
+	RequestTimeout time.Duration
+	Timeout        time.Duration
+	Provider       string
+	ProviderFor    map[string]string
+	Providers      map[string]ProviderConfig
+	Model          string
+	Temperature    float32
+	BrandVoice     string
+	GlossaryFile   string
+	PromptTemplate string       // 기본 프롬프트 템플릿 파일 (빈 값이면 내장 템플릿)
+	PromptRules    []PromptRule // 파일/언어별 프롬프트 템플릿
+	LengthRules    []LengthRule // 파일/키 glob별 길이 제한
+	PluralKeys     bool         // i18next 복수형 키를 대상 언어 범주에 맞게 확장
+	MemoryFile     string       // 번역 메모리 파일
+	NoMemory       bool
+	ApprovalsFile  string // 사람이 승인한 키 기록
+	DryRun         bool   // 요청 없이 프롬프트 토큰 수와 비용만 계산
+	Pricing        priceTable
+	ReportFile     string       // 작업별 결과와 토큰 사용량, 비용을 담은 실행 보고서 (JSON)
+	Budget         budgetLimits // 실행 전체 지출 한도
+	LanguageBudget budgetLimits // 언어별 지출 한도
+	RateLimit      rateLimits   // 백엔드별 분당 요청/토큰 한도 (providers.<name>.rateLimit이 우선)
+
+	memory    *translationMemory // 실행 중 열린 번역 메모리 (비활성화 시 nil)
+	glossary  *glossary          // 프로젝트 용어집 (파일이 없으면 nil)
+	prompts   *promptSet
+	approvals *approvals // 사람이 승인해 덮어쓰지 않는 키
+}
+
+const usageText = `Usage: go-multilingual <command> [flags]
+
+Commands:
+  translate   Translate a source locale file into target languages
+  tm          Export, import or prune the translation memory
+  xliff       Export translations for review as XLIFF, or import reviewed XLIFF
+  check       Check translations against the source without calling a model
+  languages   List language codes known to the tool
+  help        Show this help
+
+Run 'go-multilingual <command> -h' for command flags.
+`
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, usageText)
+}
+
+// 쉼표로 구분된 목록을 파싱 (공백과 빈 항목 제거)
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseTranslateFlags(args []string) (*translateOptions, error) {
+	fs := flag.NewFlagSet("translate", flag.ContinueOnError)
+
+	opts := &translateOptions{BrandVoice: DEFAULT_BRAND_VOICE}
+	var project projectFlags
+	var providerFor string
+	var temperature float64
+	project.register(fs, opts)
+	fs.BoolVar(&opts.Incremental, "incremental", false, "translate only new or changed keys and keep existing translations")
+	fs.StringVar(&opts.StateFile, "state-file", DEFAULT_STATE_FILE, "file recording per-job status of the last run")
+	fs.StringVar(&opts.ReportFile, "report", DEFAULT_REPORT_FILE, "JSON run report with per-job, per-language and per-file token usage and cost")
+	fs.BoolVar(&opts.Resume, "resume", false, "rerun only the failed or unfinished jobs recorded in --state-file")
+	fs.StringVar(&opts.Provider, "provider", DEFAULT_PROVIDER, "translation provider: "+strings.Join(providerNames, ", "))
+	fs.StringVar(&providerFor, "provider-for", "", "per-language providers, e.g. km=google,my=deepl")
+	fs.StringVar(&opts.Model, "model", "", "model or deployment name for openai, azure and local providers")
+	fs.Float64Var(&temperature, "temperature", DEFAULT_TEMPERATURE, "sampling temperature for chat providers")
+	fs.IntVar(&opts.Jobs, "jobs", MAX_CONCURRENT_JOBS, "maximum concurrent language×file jobs")
+	fs.IntVar(&opts.ChunkJobs, "chunk-jobs", MAX_CONCURRENT_CHUNKS, "maximum concurrent chunks per job")
+	fs.IntVar(&opts.ChunkTokens, "chunk-tokens", DEFAULT_CHUNK_TOKENS, "approximate source token budget per request chunk")
+	fs.IntVar(&opts.MaxRetries, "retries", MAX_RETRIES, "maximum retries per request after the first attempt")
+	fs.DurationVar(&opts.RetryDelay, "retry-delay", time.Second*RETRY_DELAY, "initial retry delay, doubled on each retry with jitter")
+	fs.DurationVar(&opts.MaxRetryDelay, "max-retry-delay", time.Second*MAX_RETRY_DELAY, "upper bound for the retry delay (Retry-After may exceed it)")
+	fs.DurationVar(&opts.RequestTimeout, "request-timeout", DEFAULT_REQUEST_TIMEOUT, "timeout for a single translation request (0 disables)")
+	fs.DurationVar(&opts.Timeout, "timeout", 0, "overall deadline for the run; completed files are kept (0 disables)")
+	fs.IntVar(&opts.RepairAttempts, "repair-attempts", DEFAULT_REPAIR_ATTEMPTS, "times to re-request keys missing or retyped in the model response (0 fails immediately)")
+	fs.StringVar(&opts.PromptTemplate, "prompt-template", "", "default prompt template file (Go text/template; built-in template if empty)")
+	fs.StringVar(&opts.MemoryFile, "tm", DEFAULT_TM_FILE, "translation memory file reused across runs")
+	fs.BoolVar(&opts.NoMemory, "no-tm", false, "do not read or write the translation memory")
+	fs.Int64Var(&opts.Budget.MaxTokens, "max-tokens", 0, "stop the run once prompt+completion tokens would exceed this (0 disables)")
+	fs.Float64Var(&opts.Budget.MaxCost, "max-cost", 0, "stop the run once the cost in USD (per pricing) would exceed this (0 disables)")
+	fs.Int64Var(&opts.LanguageBudget.MaxTokens, "max-tokens-per-language", 0, "stop a language once its tokens would exceed this (0 disables)")
+	fs.Float64Var(&opts.LanguageBudget.MaxCost, "max-cost-per-language", 0, "stop a language once its cost in USD would exceed this (0 disables)")
+	fs.IntVar(&opts.RateLimit.RequestsPerMinute, "requests-per-minute", 0, "client-side limit on requests per minute for each backend (0: only limits learned from x-ratelimit-* headers)")
+	fs.Int64Var(&opts.RateLimit.TokensPerMinute, "tokens-per-minute", 0, "client-side limit on prompt+completion tokens per minute for each chat backend (0: only limits learned from headers)")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "build the prompts and print estimated tokens and cost per language without sending requests")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	opts.Temperature = float32(temperature)
+
+	var err error
+	if opts.ProviderFor, err = parseAssignments(providerFor); err != nil {
+		return nil, fmt.Errorf("invalid --provider-for: %v", err)
+	}
+	if err := project.load(fs, opts); err != nil {
+		return nil, err
+	}
+	if opts.prompts, err = loadPromptSet(opts.PromptTemplate, opts.PromptRules); err != nil {
+		return nil, err
+	}
+
+	if !opts.Resume && !opts.AllLanguages && len(opts.Targets) == 0 {
+		return nil, fmt.Errorf("no target languages: use --to, --all-from-languageMap or targets in %s", project.configPath)
+	}
+	if opts.Jobs <= 0 || opts.ChunkJobs <= 0 {
+		return nil, fmt.Errorf("--jobs and --chunk-jobs must be positive")
+	}
+	if opts.MaxRetries < 0 {
+		return nil, fmt.Errorf("--retries must not be negative")
+	}
+	if opts.Budget.MaxTokens < 0 || opts.Budget.MaxCost < 0 || opts.LanguageBudget.MaxTokens < 0 || opts.LanguageBudget.MaxCost < 0 {
+		return nil, fmt.Errorf("budget limits must not be negative")
+	}
+	if opts.RateLimit.RequestsPerMinute < 0 || opts.RateLimit.TokensPerMinute < 0 {
+		return nil, fmt.Errorf("rate limits must not be negative")
+	}
+	return opts, nil
+}
+
+// 소스 파일, 대상 언어, 출력 경로처럼 translate와 다른 명령이 함께 쓰는 플래그
+type projectFlags struct {
+	configPath, include, excludeFiles, to, exclude string
+}
+
+func (p *projectFlags) register(fs *flag.FlagSet, opts *translateOptions) {
+	fs.StringVar(&p.configPath, "config", DEFAULT_CONFIG_FILE, "project config file (YAML)")
+	fs.StringVar(&opts.Source, "source", "locales/en", "source locale directory or a single locale file")
+	fs.StringVar(&opts.SourceLang, "source-lang", "en", "language code of the source file")
+	fs.StringVar(&p.include, "include", "**/*.json", "comma-separated file globs to translate, relative to the source directory (.json, .arb, .yml, .po, .xml, .strings, .stringsdict)")
+	fs.StringVar(&p.excludeFiles, "exclude-files", "", "comma-separated file globs to skip, relative to the source directory")
+	fs.StringVar(&p.to, "to", "", "comma-separated target language codes (e.g. id,vi,ur)")
+	fs.BoolVar(&opts.AllLanguages, "all-from-languageMap", false, "translate into every language in languageMap except the source language")
+	fs.StringVar(&p.exclude, "exclude", "", "comma-separated language codes to skip")
+	fs.StringVar(&opts.OutDir, "out-dir", "locales", "root directory for translated locale files")
+	fs.StringVar(&opts.SnapshotDir, "snapshot-dir", ".i18n/snapshots", "directory for per-language snapshots of the translated source")
+	fs.StringVar(&opts.ApprovalsFile, "approvals", DEFAULT_APPROVALS_FILE, "file recording human-approved keys that translation never overwrites")
+	fs.BoolVar(&opts.PluralKeys, "plural-keys", true, "add and remove i18next plural keys (item_one, item_few, ...) to match each target language")
+	fs.StringVar(&opts.GlossaryFile, "glossary", DEFAULT_GLOSSARY_FILE, "glossary of approved and do-not-translate terms (YAML)")
+}
+
+// 플래그 값을 옵션에 반영하고 설정 파일과 용어집을 읽는다. 명시적으로 지정된 플래그가 설정 파일보다 우선한다.
+func (p *projectFlags) load(fs *flag.FlagSet, opts *translateOptions) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	opts.Include = splitList(p.include)
+	opts.ExcludeFiles = splitList(p.excludeFiles)
+	opts.Targets = splitList(p.to)
+	opts.Exclude = splitList(p.exclude)
+
+	config, err := loadConfig(p.configPath, set["config"])
+	if err != nil {
+		return err
+	}
+	config.applyTo(opts, set)
+
+	if opts.glossary, err = loadGlossary(opts.GlossaryFile, set["glossary"] || config.Prompt.Glossary != ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+// "lang=value,lang=value" 형식 파싱
+func parseAssignments(value string) (map[string]string, error) {
+	assignments := make(map[string]string)
+	for _, item := range splitList(value) {
+		key, val, ok := strings.Cut(item, "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if !ok || key == "" || val == "" {
+			return nil, fmt.Errorf("expected lang=value, got %q", item)
+		}
+		assignments[key] = val
+	}
+	return assignments, nil
+}
+
+// 대상 언어 목록 계산 (--to, --all-from-languageMap, --exclude 조합)
+func resolveTargetLanguages(opts *translateOptions) []string {
+	excluded := map[string]bool{opts.SourceLang: true}
+	for _, lang := range opts.Exclude {
+		excluded[lang] = true
+	}
+
+	seen := make(map[string]bool)
+	var targets []string
+	add := func(lang string) {
+		if excluded[lang] || seen[lang] {
+			return
+		}
+		seen[lang] = true
+		targets = append(targets, lang)
+	}
+
+	if opts.AllLanguages {
+		var all []string
+		for langCode := range languageMap {
+			all = append(all, langCode)
+		}
+		sort.Strings(all)
+		for _, lang := range all {
+			add(lang)
+		}
+	}
+	for _, lang := range opts.Targets {
+		add(lang)
+	}
+	return targets
+}
+
+// 지원 언어 목록 출력
+func printLanguages(w io.Writer) {
+	var codes []string
+	for code := range languageMap {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "%-4s %s\n", code, languageMap[code])
+	}
+}
+
+// tm 서브커맨드: go-multilingual tm <export|import|prune> [flags]
+func runMemoryCommand(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: go-multilingual tm <export|import|prune> [flags]")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("tm "+action, flag.ContinueOnError)
+	var configPath, memoryFile, file, olderThan, model, lang string
+	fs.StringVar(&configPath, "config", DEFAULT_CONFIG_FILE, "project config file (YAML)")
+	fs.StringVar(&memoryFile, "tm", DEFAULT_TM_FILE, "translation memory file")
+	switch action {
+	case "export":
+		fs.StringVar(&file, "out", "-", "JSON Lines file to write (- for stdout)")
+	case "import":
+		fs.StringVar(&file, "in", "-", "JSON Lines file to read (- for stdin)")
+	case "prune":
+		fs.StringVar(&olderThan, "older-than", "", "remove entries not used for this long (e.g. 90d, 720h)")
+		fs.StringVar(&model, "model", "", "remove entries of this provider:model (e.g. openai:gpt-4o)")
+		fs.StringVar(&lang, "lang", "", "remove entries of this target language")
+	default:
+		return fmt.Errorf("unknown tm command %q (available: export, import, prune)", action)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	config, err := loadConfig(configPath, set["config"])
+	if err != nil {
+		return err
+	}
+	if !set["tm"] && config.Memory.Path != "" {
+		memoryFile = config.Memory.Path
+	}
+
+	var filter tmPruneFilter
+	if action == "prune" {
+		if olderThan == "" && model == "" && lang == "" {
+			return fmt.Errorf("tm prune needs at least one of --older-than, --model or --lang")
+		}
+		if olderThan != "" {
+			if filter.UnusedFor, err = parseAge(olderThan); err != nil {
+				return fmt.Errorf("invalid --older-than: %v", err)
+			}
+		}
+		filter.Model = model
+		filter.TargetLang = lang
+	}
+
+	memory, err := openTranslationMemory(memoryFile)
+	if err != nil {
+		return err
+	}
+	defer memory.Close()
+
+	switch action {
+	case "export":
+		w := io.Writer(os.Stdout)
+		if file != "-" {
+			f, err := os.Create(file)
+			if err != nil {
+				return fmt.Errorf("error creating %s: %v", file, err)
+			}
+			defer f.Close()
+			w = f
+		}
+		count, err := memory.Export(w)
+		if err != nil {
+			return fmt.Errorf("error exporting translation memory: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d entries from %s\n", count, memoryFile)
+
+	case "import":
+		r := io.Reader(os.Stdin)
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return fmt.Errorf("error opening %s: %v", file, err)
+			}
+			defer f.Close()
+			r = f
+		}
+		count, err := memory.Import(r)
+		if err != nil {
+			return fmt.Errorf("error importing translation memory: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Imported %d entries into %s\n", count, memoryFile)
+
+	case "prune":
+		count, err := memory.Prune(filter)
+		if err != nil {
+			return fmt.Errorf("error pruning translation memory: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Removed %d entries from %s\n", count, memoryFile)
+	}
+	return nil
+}
+
+// xliff export/import 서브커맨드
+func runXLIFFCommand(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: go-multilingual xliff <export|import> [flags]")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("xliff "+action, flag.ContinueOnError)
+	opts := &translateOptions{}
+	var project projectFlags
+	var dir string
+	project.register(fs, opts)
+	switch action {
+	case "export":
+		fs.StringVar(&dir, "dir", "xliff", "directory for the per-language <lang>.xlf files")
+	case "import":
+		fs.Usage = func() {
+			fmt.Fprintln(fs.Output(), "Usage: go-multilingual xliff import [flags] <file.xlf>...")
+			fs.PrintDefaults()
+		}
+	default:
+		return fmt.Errorf("unknown xliff command %q (available: export, import)", action)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if err := project.load(fs, opts); err != nil {
+		return err
+	}
+
+	var err error
+	if opts.approvals, err = loadApprovals(opts.ApprovalsFile); err != nil {
+		return err
+	}
+
+	if action == "export" {
+		if fs.NArg() > 0 {
+			return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+		}
+		return exportXLIFF(opts, dir)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("xliff import needs at least one XLIFF file")
+	}
+	return importXLIFF(opts, fs.Args())
+}
+
+// 모델 호출 없이 번역 누락, 오래된 번역, 남는 키, 플레이스홀더 불일치를 검사한다. 문제가 있으면 오류를 반환한다.
+func runCheckCommand(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	opts := &translateOptions{}
+	var project projectFlags
+	project.register(fs, opts)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if err := project.load(fs, opts); err != nil {
+		return err
+	}
+
+	var err error
+	if opts.approvals, err = loadApprovals(opts.ApprovalsFile); err != nil {
+		return err
+	}
+
+	languages := checkLanguages(opts)
+	if len(languages) == 0 {
+		return fmt.Errorf("no target languages found in %s: use --to, --all-from-languageMap or targets in the config file", opts.OutDir)
+	}
+	problems, err := checkTranslations(opts, languages)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("check found %d problems in %s (%s)", len(problems), strings.Join(languages, ", "), summarizeProblems(problems))
+	}
+	fmt.Printf("check passed: %s\n", strings.Join(languages, ", "))
+	return nil
+}
+
+// time.ParseDuration에 일 단위("90d")를 더한 기간 파싱
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("expected a positive number of days, got %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// 빈 설정 파일을 가리키는 --config (저장소의 go-multilingual.yaml을 읽지 않도록)
+func emptyConfigArgs(t *testing.T) []string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return []string{"--config", path}
+}
+
+func TestParseTranslateFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		check   func(t *testing.T, opts *translateOptions)
+	}{
+		{
+			name: "target and excluded languages",
+			args: []string{"--to", "id, vi,,ur", "--exclude", "vi"},
+			check: func(t *testing.T, opts *translateOptions) {
+				if want := []string{"id", "vi", "ur"}; !reflect.DeepEqual(opts.Targets, want) {
+					t.Errorf("Targets = %v, want %v", opts.Targets, want)
+				}
+				if want := []string{"vi"}; !reflect.DeepEqual(opts.Exclude, want) {
+					t.Errorf("Exclude = %v, want %v", opts.Exclude, want)
+				}
+			},
+		},
+		{
+			name: "defaults",
+			args: []string{"--all-from-languageMap"},
+			check: func(t *testing.T, opts *translateOptions) {
+				if opts.Source != "locales/en" || opts.SourceLang != "en" || opts.OutDir != "locales" {
+					t.Errorf("source %q (%s), out %q", opts.Source, opts.SourceLang, opts.OutDir)
+				}
+				if opts.Jobs != MAX_CONCURRENT_JOBS || opts.Provider != DEFAULT_PROVIDER || !opts.PluralKeys {
+					t.Errorf("jobs %d, provider %q, plural keys %v", opts.Jobs, opts.Provider, opts.PluralKeys)
+				}
+				if want := []string{"**/*.json"}; !reflect.DeepEqual(opts.Include, want) {
+					t.Errorf("Include = %v, want %v", opts.Include, want)
+				}
+			},
+		},
+		{
+			name: "per-language providers",
+			args: []string{"--to", "km", "--provider-for", "km=google, my=deepl"},
+			check: func(t *testing.T, opts *translateOptions) {
+				if want := map[string]string{"km": "google", "my": "deepl"}; !reflect.DeepEqual(opts.ProviderFor, want) {
+					t.Errorf("ProviderFor = %v, want %v", opts.ProviderFor, want)
+				}
+			},
+		},
+		{name: "resume needs no targets", args: []string{"--resume"}},
+		{name: "no target languages", args: []string{}, wantErr: true},
+		{name: "invalid provider-for", args: []string{"--to", "km", "--provider-for", "km"}, wantErr: true},
+		{name: "zero jobs", args: []string{"--to", "fr", "--jobs", "0"}, wantErr: true},
+		{name: "negative retries", args: []string{"--to", "fr", "--retries", "-1"}, wantErr: true},
+		{name: "negative budget", args: []string{"--to", "fr", "--max-cost", "-1"}, wantErr: true},
+		{name: "positional arguments", args: []string{"--to", "fr", "extra"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseTranslateFlags(append(emptyConfigArgs(t), tt.args...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && tt.check != nil {
+				tt.check(t, opts)
+			}
+		})
+	}
+}
+
+func TestResolveTargetLanguages(t *testing.T) {
+	tests := []struct {
+		name string
+		opts translateOptions
+		want []string
+	}{
+		{
+			name: "targets in order without duplicates",
+			opts: translateOptions{SourceLang: "en", Targets: []string{"fr", "de", "fr"}},
+			want: []string{"fr", "de"},
+		},
+		{
+			name: "source language is skipped",
+			opts: translateOptions{SourceLang: "en", Targets: []string{"en", "fr"}},
+			want: []string{"fr"},
+		},
+		{
+			name: "excluded languages are skipped",
+			opts: translateOptions{SourceLang: "en", Targets: []string{"fr", "de", "ja"}, Exclude: []string{"de"}},
+			want: []string{"fr", "ja"},
+		},
+		{
+			name: "no targets",
+			opts: translateOptions{SourceLang: "en"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveTargetLanguages(&tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTargetLanguagesAll(t *testing.T) {
+	opts := &translateOptions{SourceLang: "en", AllLanguages: true, Exclude: []string{"ko"}, Targets: []string{"fr", "xx"}}
+	got := resolveTargetLanguages(opts)
+
+	// languageMap의 모든 언어(정렬) 다음에 languageMap에 없는 --to 언어
+	if len(got) != len(languageMap)-2+1 {
+		t.Fatalf("%d languages, want %d", len(got), len(languageMap)-1)
+	}
+	if last := got[len(got)-1]; last != "xx" {
+		t.Errorf("last language = %q, want the extra --to language", last)
+	}
+	all := got[:len(got)-1]
+	if !sort.StringsAreSorted(all) {
+		t.Errorf("languageMap languages are not sorted: %v", all)
+	}
+	for _, lang := range got {
+		if lang == "en" || lang == "ko" {
+			t.Errorf("%s should be skipped", lang)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DEFAULT_CONFIG_FILE = "go-multilingual.yaml"
+
+// 프로젝트 설정 파일 (go-multilingual.yaml)
+// 값이 비어 있는 항목은 CLI 기본값을 사용하고, 명시적으로 지정한 CLI 플래그가 항상 우선한다.
+type Config struct {
+	Source struct {
+		Path    string   `yaml:"path"`    // 소스 로케일 디렉터리 또는 파일
+		Lang    string   `yaml:"lang"`    // 소스 언어 코드
+		Include []string `yaml:"include"` // 번역할 파일 glob (소스 디렉터리 기준, 예: "**/*.json")
+		Exclude []string `yaml:"exclude"` // 제외할 파일 glob
+	} `yaml:"source"`
+
+	Targets struct {
+		Languages []string `yaml:"languages"`
+		All       *bool    `yaml:"all"` // languageMap의 모든 언어
+		Exclude   []string `yaml:"exclude"`
+	} `yaml:"targets"`
+
+	Output struct {
+		Dir         string `yaml:"dir"`
+		SnapshotDir string `yaml:"snapshotDir"`
+		StateFile   string `yaml:"stateFile"` // --resume에 사용하는 작업 상태 파일
+		Approvals   string `yaml:"approvals"` // 사람이 승인한 키 기록 (XLIFF 가져오기)
+		Report      string `yaml:"report"`    // 토큰 사용량과 비용을 담은 실행 보고서
+	} `yaml:"output"`
+
+	Incremental *bool `yaml:"incremental"`
+	PluralKeys  *bool `yaml:"pluralKeys"` // i18next 복수형 키 확장 (기본 true)
+
+	Provider struct {
+		Name        string            `yaml:"name"`
+		Model       string            `yaml:"model"`
+		Temperature *float32          `yaml:"temperature"`
+		PerLanguage map[string]string `yaml:"perLanguage"` // 언어별 백엔드 (예: km: google)
+	} `yaml:"provider"`
+
+	// 백엔드별 인증 정보와 엔드포인트 (환경 변수가 설정되어 있으면 환경 변수가 우선)
+	Providers map[string]ProviderConfig `yaml:"providers"`
+
+	// 모델별 가격 (--dry-run 예상 비용, 실행 보고서의 비용, 지출 한도)
+	Pricing map[string]ModelPrice `yaml:"pricing"`
+
+	// 지출 한도 (예상 또는 실제 지출이 넘으면 새 작업을 시작하지 않고 진행 중인 작업을 취소)
+	Budget struct {
+		MaxTokens   int64        `yaml:"maxTokens"`
+		MaxCost     float64      `yaml:"maxCost"`
+		PerLanguage budgetLimits `yaml:"perLanguage"`
+	} `yaml:"budget"`
+
+	// 백엔드별 분당 요청/토큰 한도 (providers.<name>.rateLimit이 우선)
+	RateLimit rateLimits `yaml:"rateLimit"`
+
+	Concurrency struct {
+		Jobs        int `yaml:"jobs"`        // 동시에 처리할 언어×파일 작업 수
+		Chunks      int `yaml:"chunks"`      // 작업 하나에서 동시에 번역할 청크 수
+		ChunkTokens int `yaml:"chunkTokens"` // 청크당 소스 토큰 예산
+	} `yaml:"concurrency"`
+
+	Retry struct {
+		MaxRetries     *int          `yaml:"maxRetries"` // 첫 요청 이후 최대 재시도 횟수
+		Delay          time.Duration `yaml:"delay"`      // 첫 재시도 대기 시간 (이후 지수 백오프)
+		MaxDelay       time.Duration `yaml:"maxDelay"`   // 백오프 상한
+		RepairAttempts *int          `yaml:"repairAttempts"`
+	} `yaml:"retry"`
+
+	Timeouts struct {
+		Request time.Duration `yaml:"request"` // 요청 하나의 시간 제한
+		Run     time.Duration `yaml:"run"`     // 실행 전체의 시간 제한
+	} `yaml:"timeouts"`
+
+	// 파일/키 glob별 번역 길이 제한 (키별 제한은 @key 메타데이터나 맥락 파일의 maxLength/maxRatio)
+	LengthLimits []LengthRule `yaml:"lengthLimits"`
+
+	Memory struct {
+		Path    string `yaml:"path"`    // 번역 메모리 파일
+		Enabled *bool  `yaml:"enabled"` // false면 번역 메모리를 사용하지 않음
+	} `yaml:"memory"`
+
+	Prompt struct {
+		BrandVoice string       `yaml:"brandVoice"` // 프롬프트의 브랜드 보이스 지침
+		Glossary   string       `yaml:"glossary"`   // 용어집 파일
+		Template   string       `yaml:"template"`   // 기본 프롬프트 템플릿 파일
+		Templates  []PromptRule `yaml:"templates"`  // 파일 glob/언어별 템플릿 (먼저 나온 규칙 우선)
+	} `yaml:"prompt"`
+}
+
+// 번역 백엔드 설정
+type ProviderConfig struct {
+	APIKey     string     `yaml:"apiKey"`
+	BaseURL    string     `yaml:"baseURL"`    // Azure 엔드포인트, 로컬 LLM base URL, DeepL/Google API URL
+	Model      string     `yaml:"model"`      // 모델 또는 Azure 배포 이름
+	APIVersion string     `yaml:"apiVersion"` // Azure API 버전
+	RateLimit  rateLimits `yaml:"rateLimit"`  // 이 백엔드의 분당 요청/토큰 한도
+}
+
+// 설정 파일 로드
+// 기본 경로의 파일이 없으면 빈 설정을 반환하고, 명시적으로 지정한 파일이 없으면 오류를 반환한다.
+func loadConfig(path string, explicit bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+	return &config, nil
+}
+
+// 설정 값을 옵션에 반영한다. set에 있는(명시적으로 지정된) 플래그는 덮어쓰지 않는다.
+func (c *Config) applyTo(opts *translateOptions, set map[string]bool) {
+	setString := func(flagName string, dst *string, value string) {
+		if !set[flagName] && value != "" {
+			*dst = value
+		}
+	}
+	setList := func(flagName string, dst *[]string, value []string) {
+		if !set[flagName] && len(value) > 0 {
+			*dst = value
+		}
+	}
+	setInt := func(flagName string, dst *int, value int) {
+		if !set[flagName] && value > 0 {
+			*dst = value
+		}
+	}
+
+	setString("source", &opts.Source, c.Source.Path)
+	setString("source-lang", &opts.SourceLang, c.Source.Lang)
+	setList("include", &opts.Include, c.Source.Include)
+	setList("exclude-files", &opts.ExcludeFiles, c.Source.Exclude)
+
+	setList("to", &opts.Targets, c.Targets.Languages)
+	if !set["all-from-languageMap"] && c.Targets.All != nil {
+		opts.AllLanguages = *c.Targets.All
+	}
+	setList("exclude", &opts.Exclude, c.Targets.Exclude)
+
+	setString("out-dir", &opts.OutDir, c.Output.Dir)
+	setString("snapshot-dir", &opts.SnapshotDir, c.Output.SnapshotDir)
+	setString("state-file", &opts.StateFile, c.Output.StateFile)
+	setString("approvals", &opts.ApprovalsFile, c.Output.Approvals)
+	setString("report", &opts.ReportFile, c.Output.Report)
+	if !set["incremental"] && c.Incremental != nil {
+		opts.Incremental = *c.Incremental
+	}
+	if !set["plural-keys"] && c.PluralKeys != nil {
+		opts.PluralKeys = *c.PluralKeys
+	}
+
+	setString("provider", &opts.Provider, c.Provider.Name)
+	setString("model", &opts.Model, c.Provider.Model)
+	if !set["temperature"] && c.Provider.Temperature != nil {
+		opts.Temperature = *c.Provider.Temperature
+	}
+	if !set["provider-for"] && len(c.Provider.PerLanguage) > 0 {
+		opts.ProviderFor = c.Provider.PerLanguage
+	}
+	opts.Providers = c.Providers
+	opts.Pricing = c.Pricing
+	if !set["max-tokens"] && c.Budget.MaxTokens > 0 {
+		opts.Budget.MaxTokens = c.Budget.MaxTokens
+	}
+	if !set["max-cost"] && c.Budget.MaxCost > 0 {
+		opts.Budget.MaxCost = c.Budget.MaxCost
+	}
+	if !set["max-tokens-per-language"] && c.Budget.PerLanguage.MaxTokens > 0 {
+		opts.LanguageBudget.MaxTokens = c.Budget.PerLanguage.MaxTokens
+	}
+	if !set["max-cost-per-language"] && c.Budget.PerLanguage.MaxCost > 0 {
+		opts.LanguageBudget.MaxCost = c.Budget.PerLanguage.MaxCost
+	}
+
+	setInt("requests-per-minute", &opts.RateLimit.RequestsPerMinute, c.RateLimit.RequestsPerMinute)
+	if !set["tokens-per-minute"] && c.RateLimit.TokensPerMinute > 0 {
+		opts.RateLimit.TokensPerMinute = c.RateLimit.TokensPerMinute
+	}
+
+	setInt("jobs", &opts.Jobs, c.Concurrency.Jobs)
+	setInt("chunk-jobs", &opts.ChunkJobs, c.Concurrency.Chunks)
+	setInt("chunk-tokens", &opts.ChunkTokens, c.Concurrency.ChunkTokens)
+
+	if !set["retries"] && c.Retry.MaxRetries != nil {
+		opts.MaxRetries = *c.Retry.MaxRetries
+	}
+	if !set["retry-delay"] && c.Retry.Delay > 0 {
+		opts.RetryDelay = c.Retry.Delay
+	}
+	if !set["max-retry-delay"] && c.Retry.MaxDelay > 0 {
+		opts.MaxRetryDelay = c.Retry.MaxDelay
+	}
+	if !set["repair-attempts"] && c.Retry.RepairAttempts != nil {
+		opts.RepairAttempts = *c.Retry.RepairAttempts
+	}
+
+	if !set["request-timeout"] && c.Timeouts.Request > 0 {
+		opts.RequestTimeout = c.Timeouts.Request
+	}
+	if !set["timeout"] && c.Timeouts.Run > 0 {
+		opts.Timeout = c.Timeouts.Run
+	}
+
+	setString("tm", &opts.MemoryFile, c.Memory.Path)
+	if !set["no-tm"] && c.Memory.Enabled != nil {
+		opts.NoMemory = !*c.Memory.Enabled
+	}
+
+	setString("glossary", &opts.GlossaryFile, c.Prompt.Glossary)
+	setString("prompt-template", &opts.PromptTemplate, c.Prompt.Template)
+	opts.PromptRules = c.Prompt.Templates
+	opts.LengthRules = c.LengthLimits
+	if c.Prompt.BrandVoice != "" {
+		opts.BrandVoice = c.Prompt.BrandVoice
+	}
+}
+
+// 환경 변수가 설정되어 있으면 환경 변수를, 아니면 설정 파일 값을 사용
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testConfig = `
+source:
+  path: src/en
+targets:
+  languages: [fr, de]
+  all: true
+incremental: true
+pluralKeys: false
+provider:
+  model: gpt-test
+concurrency:
+  jobs: 7
+retry:
+  maxRetries: 0
+  delay: 3s
+timeouts:
+  request: 90s
+memory:
+  enabled: false
+`
+
+func TestConfigApplyTo(t *testing.T) {
+	var config Config
+	if err := yaml.Unmarshal([]byte(testConfig), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	// 기본값으로 채운 옵션에 설정을 반영한다. 플래그로 지정한 값은 기본값과 다른 값으로 표시한다.
+	defaults := func() *translateOptions {
+		return &translateOptions{
+			Source:         "locales/en",
+			Jobs:           MAX_CONCURRENT_JOBS,
+			MaxRetries:     MAX_RETRIES,
+			RetryDelay:     time.Second * RETRY_DELAY,
+			MaxRetryDelay:  time.Second * MAX_RETRY_DELAY,
+			RequestTimeout: DEFAULT_REQUEST_TIMEOUT,
+			PluralKeys:     true,
+		}
+	}
+	tests := []struct {
+		name  string
+		flags func(opts *translateOptions)
+		set   []string
+		check func(t *testing.T, opts *translateOptions)
+	}{
+		{
+			name: "config beats built-in defaults",
+			check: func(t *testing.T, opts *translateOptions) {
+				if opts.Source != "src/en" || opts.Model != "gpt-test" || opts.Jobs != 7 {
+					t.Errorf("source %q, model %q, jobs %d", opts.Source, opts.Model, opts.Jobs)
+				}
+				if want := []string{"fr", "de"}; !reflect.DeepEqual(opts.Targets, want) {
+					t.Errorf("Targets = %v, want %v", opts.Targets, want)
+				}
+				if !opts.AllLanguages || !opts.Incremental || opts.PluralKeys || !opts.NoMemory {
+					t.Errorf("all %v, incremental %v, plural keys %v, no tm %v", opts.AllLanguages, opts.Incremental, opts.PluralKeys, opts.NoMemory)
+				}
+				if opts.MaxRetries != 0 {
+					t.Errorf("MaxRetries = %d, want the explicit 0 from config", opts.MaxRetries)
+				}
+				if opts.RetryDelay != 3*time.Second || opts.RequestTimeout != 90*time.Second {
+					t.Errorf("retry delay %v, request timeout %v", opts.RetryDelay, opts.RequestTimeout)
+				}
+			},
+		},
+		{
+			name: "unset config values keep the defaults",
+			check: func(t *testing.T, opts *translateOptions) {
+				if opts.MaxRetryDelay != time.Second*MAX_RETRY_DELAY || opts.Timeout != 0 || opts.Provider != "" {
+					t.Errorf("max retry delay %v, timeout %v, provider %q", opts.MaxRetryDelay, opts.Timeout, opts.Provider)
+				}
+			},
+		},
+		{
+			name: "explicit string and int flags win",
+			flags: func(opts *translateOptions) {
+				opts.Source = "cli/en"
+				opts.Targets = []string{"ja"}
+				opts.Jobs = 2
+				opts.MaxRetries = 5
+			},
+			set: []string{"source", "to", "jobs", "retries"},
+			check: func(t *testing.T, opts *translateOptions) {
+				if opts.Source != "cli/en" || opts.Jobs != 2 || opts.MaxRetries != 5 {
+					t.Errorf("source %q, jobs %d, retries %d", opts.Source, opts.Jobs, opts.MaxRetries)
+				}
+				if want := []string{"ja"}; !reflect.DeepEqual(opts.Targets, want) {
+					t.Errorf("Targets = %v, want %v", opts.Targets, want)
+				}
+			},
+		},
+		{
+			name: "explicit bool flags win over config pointers",
+			flags: func(opts *translateOptions) {
+				opts.Incremental = false
+				opts.PluralKeys = true
+				opts.AllLanguages = false
+			},
+			set: []string{"incremental", "plural-keys", "all-from-languageMap", "no-tm"},
+			check: func(t *testing.T, opts *translateOptions) {
+				if opts.AllLanguages || opts.Incremental || !opts.PluralKeys || opts.NoMemory {
+					t.Errorf("all %v, incremental %v, plural keys %v, no tm %v", opts.AllLanguages, opts.Incremental, opts.PluralKeys, opts.NoMemory)
+				}
+			},
+		},
+		{
+			name: "explicit duration flags win",
+			flags: func(opts *translateOptions) {
+				opts.RetryDelay = time.Second
+				opts.RequestTimeout = 0
+			},
+			set: []string{"retry-delay", "request-timeout"},
+			check: func(t *testing.T, opts *translateOptions) {
+				if opts.RetryDelay != time.Second || opts.RequestTimeout != 0 {
+					t.Errorf("retry delay %v, request timeout %v", opts.RetryDelay, opts.RequestTimeout)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := defaults()
+			if tt.flags != nil {
+				tt.flags(opts)
+			}
+			set := make(map[string]bool)
+			for _, name := range tt.set {
+				set[name] = true
+			}
+			config.applyTo(opts, set)
+			tt.check(t, opts)
+		})
+	}
+}
+
+func TestParseTranslateFlagsConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 기본값과 같은 값이라도 명시적으로 지정한 플래그는 설정 파일보다 우선한다
+	opts, err := parseTranslateFlags([]string{"--config", path, "--jobs", "4", "--plural-keys=true", "--request-timeout", "0s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Jobs != 4 || !opts.PluralKeys || opts.RequestTimeout != 0 {
+		t.Errorf("jobs %d, plural keys %v, request timeout %v", opts.Jobs, opts.PluralKeys, opts.RequestTimeout)
+	}
+	if opts.Source != "src/en" || opts.RetryDelay != 3*time.Second || !opts.Incremental {
+		t.Errorf("source %q, retry delay %v, incremental %v", opts.Source, opts.RetryDelay, opts.Incremental)
+	}
+	if opts.ChunkTokens != DEFAULT_CHUNK_TOKENS {
+		t.Errorf("ChunkTokens = %d, want the default %d", opts.ChunkTokens, DEFAULT_CHUNK_TOKENS)
+	}
+
+	if _, err := parseTranslateFlags([]string{"--config", filepath.Join(t.TempDir(), "missing.yaml"), "--to", "fr"}); err == nil {
+		t.Error("missing explicit config file: want an error")
+	}
+}
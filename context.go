@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 키별 맥락 정보 (모델에 전달되며 대상 파일에는 쓰지 않는다)
+type keyContext struct {
+	Description string  `yaml:"description"` // 어디에 어떻게 쓰이는 문자열인지
+	MaxLength   int     `yaml:"maxLength"`   // 최대 글자 수 (0이면 제한 없음)
+	MaxRatio    float64 `yaml:"maxRatio"`    // 소스 길이 대비 최대 비율 (작업 시작 시 MaxLength로 환산)
+	Screenshot  string  `yaml:"screenshot"`  // 스크린샷 경로 또는 URL
+}
+
+// 프롬프트에 넣을 한 줄 설명
+func (c keyContext) note() string {
+	var parts []string
+	if c.Description != "" {
+		parts = append(parts, c.Description)
+	}
+	if c.MaxLength > 0 {
+		parts = append(parts, fmt.Sprintf("at most %d characters", c.MaxLength))
+	}
+	if c.Screenshot != "" {
+		parts = append(parts, "screenshot: "+c.Screenshot)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// 키 경로(JSON Pointer) → 맥락
+type contextNotes map[string]keyContext
+
+// 소스 파일 옆의 맥락 파일 경로 (예: auth.json → auth.context.yaml)
+func contextSidecarPath(sourcePath string) string {
+	ext := ""
+	if i := strings.LastIndex(sourcePath, "."); i > strings.LastIndexAny(sourcePath, `/\`) {
+		ext = sourcePath[i:]
+	}
+	return strings.TrimSuffix(sourcePath, ext) + ".context.yaml"
+}
+
+// 맥락 파일 로드 (파일이 없으면 빈 맥락)
+// 키는 JSON Pointer 경로이며, 값은 설명 문자열이나 description/maxLength/screenshot 객체다.
+func loadContextSidecar(path string) (contextNotes, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return contextNotes{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading context file: %v", err)
+	}
+
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing context file %s: %v", path, err)
+	}
+	notes := make(contextNotes, len(raw))
+	for key, node := range raw {
+		if !strings.HasPrefix(key, "/") {
+			return nil, fmt.Errorf("context file %s: key %q must be a JSON Pointer such as /buttons/close", path, key)
+		}
+		var ctx keyContext
+		if node.Kind == yaml.ScalarNode {
+			ctx.Description = node.Value
+		} else if err := node.Decode(&ctx); err != nil {
+			return nil, fmt.Errorf("context file %s: %s: %v", path, key, err)
+		}
+		notes[key] = ctx
+	}
+	return notes, nil
+}
+
+// ARB 방식의 "@key" 메타데이터를 소스 트리에서 떼어 내 맥락으로 반환한다.
+// "@@locale" 같은 파일 수준 메타데이터는 버린다.
+// 일반 JSON에서는 같은 객체에 "key"가 있는 "@key"만 메타데이터로 보고, 나머지 "@"로 시작하는 키는 번역한다.
+func extractKeyMetadata(node interface{}, path string, notes contextNotes, arb bool) interface{} {
+	switch v := node.(type) {
+	case *orderedMap:
+		stripped := newOrderedMap()
+		for _, key := range v.Keys() {
+			value, _ := v.Get(key)
+			if isKeyMetadata(v, key, arb) {
+				if target := strings.TrimPrefix(key, "@"); !strings.HasPrefix(target, "@") {
+					notes[joinPointer(path, target)] = metadataContext(value)
+				}
+				continue
+			}
+			stripped.Set(key, extractKeyMetadata(value, joinPointer(path, key), notes, arb))
+		}
+		return stripped
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = extractKeyMetadata(item, joinPointer(path, fmt.Sprint(i)), notes, arb)
+		}
+		return items
+	}
+	return node
+}
+
+func isKeyMetadata(m *orderedMap, key string, arb bool) bool {
+	if !strings.HasPrefix(key, "@") {
+		return false
+	}
+	if arb {
+		return true
+	}
+	_, ok := m.Get(strings.TrimPrefix(key, "@"))
+	return ok
+}
+
+// "@key" 값 해석: 문자열이면 설명, 객체면 description(ARB의 context 포함)/maxLength/maxRatio/screenshot
+func metadataContext(value interface{}) keyContext {
+	var ctx keyContext
+	switch v := value.(type) {
+	case string:
+		ctx.Description = v
+	case *orderedMap:
+		var parts []string
+		for _, field := range []string{"description", "context"} {
+			if text, ok := lookupString(v, field); ok && text != "" {
+				parts = append(parts, text)
+			}
+		}
+		ctx.Description = strings.Join(parts, " ")
+		if value, ok := v.Get("maxLength"); ok {
+			if n, ok := value.(float64); ok {
+				ctx.MaxLength = int(n)
+			}
+		}
+		if value, ok := v.Get("maxRatio"); ok {
+			ctx.MaxRatio, _ = value.(float64)
+		}
+		ctx.Screenshot, _ = lookupString(v, "screenshot")
+	}
+	return ctx
+}
+
+func lookupString(m *orderedMap, key string) (string, bool) {
+	value, ok := m.Get(key)
+	if !ok {
+		return "", false
+	}
+	text, ok := value.(string)
+	return text, ok
+}
+
+// 소스 파일의 맥락: "@key" 메타데이터에 맥락 파일 항목을 덮어쓴다.
+func (notes contextNotes) merge(other contextNotes) {
+	for key, ctx := range other {
+		notes[key] = ctx
+	}
+}
+
+// 콘텐츠에 포함된 문자열 키의 맥락 (키 순서 유지)
+func (notes contextNotes) forContent(content interface{}) []promptContextNote {
+	if len(notes) == 0 {
+		return nil
+	}
+	var result []promptContextNote
+	walkLeaves(content, "", func(path string, value interface{}) {
+		ctx, ok := notes[path]
+		if !ok {
+			return
+		}
+		if _, isText := value.(string); !isText {
+			return
+		}
+		result = append(result, promptContextNote{
+			Key:         path,
+			Note:        ctx.note(),
+			Description: ctx.Description,
+			MaxLength:   ctx.MaxLength,
+			Screenshot:  ctx.Screenshot,
+		})
+	})
+	return result
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DEEPL_FREE_API_URL = "https://api-free.deepl.com/v2/translate"
+	DEEPL_PRO_API_URL  = "https://api.deepl.com/v2/translate"
+	DEEPL_BATCH_SIZE   = 50 // 요청당 최대 텍스트 수
+)
+
+// DeepL REST API 기반 백엔드
+type deeplTranslator struct {
+	apiKey     string
+	apiURL     string
+	httpClient *http.Client
+	limiter    *rateLimiter // 분당 요청 한도 (배치 요청마다 적용)
+}
+
+func newDeepLTranslator(apiKey, apiURL string, limiter *rateLimiter) *deeplTranslator {
+	if apiURL == "" {
+		// 무료 키는 ":fx"로 끝난다
+		apiURL = DEEPL_PRO_API_URL
+		if strings.HasSuffix(apiKey, ":fx") {
+			apiURL = DEEPL_FREE_API_URL
+		}
+	}
+	return &deeplTranslator{
+		apiKey:     apiKey,
+		apiURL:     apiURL,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		limiter:    limiter,
+	}
+}
+
+func (t *deeplTranslator) Name() string {
+	return "deepl"
+}
+
+func (t *deeplTranslator) PromptHash() string {
+	return ""
+}
+
+func (t *deeplTranslator) WithPrompt(*promptTemplate) Translator {
+	return t
+}
+
+func (t *deeplTranslator) WithContext(contextNotes) Translator {
+	return t
+}
+
+// DeepL 언어 코드 (대문자, 일부 대상 언어는 지역 변형 필요)
+func deeplLanguageCode(lang string, target bool) string {
+	if target {
+		switch lang {
+		case "en":
+			return "EN-US"
+		case "pt":
+			return "PT-PT"
+		case "zh":
+			return "ZH-HANS"
+		}
+	}
+	if lang == "no" {
+		return "NB"
+	}
+	return strings.ToUpper(lang)
+}
+
+func (t *deeplTranslator) Translate(ctx context.Context, content interface{}, sourceLang, targetLang string) (interface{}, error) {
+	return translateLeaves(ctx, content, DEEPL_BATCH_SIZE, func(texts []string) ([]string, error) {
+		if err := t.limiter.wait(ctx, 0); err != nil {
+			return nil, err
+		}
+		body, err := json.Marshal(map[string]interface{}{
+			"text":         texts,
+			"source_lang":  deeplLanguageCode(sourceLang, false),
+			"target_lang":  deeplLanguageCode(targetLang, true),
+			"tag_handling": "html",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.apiURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := t.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("deepl request error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("deepl response error: %v", err)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			t.limiter.pause(parseRetryAfter(resp.Header))
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, &providerError{
+				StatusCode: resp.StatusCode,
+				RetryAfter: parseRetryAfter(resp.Header),
+				Err:        fmt.Errorf("deepl error: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data))),
+			}
+		}
+
+		var result struct {
+			Translations []struct {
+				Text string `json:"text"`
+			} `json:"translations"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("deepl response parsing error: %v", err)
+		}
+
+		translated := make([]string, len(result.Translations))
+		for i, item := range result.Translations {
+			// tag_handling=html 응답은 &amp; 등의 엔티티가 이스케이프되어 돌아온다
+			translated[i] = html.UnescapeString(item.Text)
+		}
+		return translated, nil
+	})
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeepLTranslateUnescapesHTML(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Text       []string `json:"text"`
+			TagHandler string   `json:"tag_handling"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		sent = append(sent, body.Text...)
+		// DeepL의 HTML 모드처럼 태그 밖의 텍스트를 이스케이프해 돌려준다
+		var translations []map[string]string
+		for _, text := range body.Text {
+			escaped := strings.NewReplacer("& ", "&amp; ", "\"", "&quot;", "'", "&#39;").Replace(text)
+			translations = append(translations, map[string]string{"text": escaped})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"translations": translations})
+	}))
+	defer server.Close()
+
+	translator := newDeepLTranslator("key", server.URL, nil)
+	source := mustParse(t, `{"a":"Terms & \"Conditions\"","b":"Hi {{name}}, it's <b>you</b>"}`)
+	result, err := translator.Translate(context.Background(), source, "en", "de")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	for path, want := range map[string]string{"/a": `Terms & "Conditions"`, "/b": "Hi {{name}}, it's <b>you</b>"} {
+		if got := flattenTree(result)[path]; got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+	for _, text := range sent {
+		if strings.Contains(text, "{{name}}") && !strings.Contains(text, `<span translate="no">{{name}}</span>`) {
+			t.Errorf("placeholder not protected in request: %s", text)
+		}
+	}
+}
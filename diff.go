@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// 증분 번역 계획
+// 소스, 기존 대상 파일, 이전 실행 시점의 소스 스냅샷을 비교해 번역이 필요한 키만 고른다.
+type translationPlan struct {
+	Pending  map[string]bool        // 새로 번역할 리프 경로 (추가되었거나 소스가 변경된 키)
+	Existing map[string]interface{} // 기존 대상 파일의 리프 값
+	Added    int
+	Modified int
+	Removed  int
+}
+
+// 소스/대상/스냅샷의 평탄화된 리프를 비교한다.
+// 스냅샷이 없으면 변경 여부를 알 수 없으므로 대상 파일에 이미 있는 키는 그대로 둔다.
+func planIncremental(source, existing, snapshot map[string]interface{}) *translationPlan {
+	plan := &translationPlan{
+		Pending:  make(map[string]bool),
+		Existing: existing,
+	}
+
+	for path, value := range source {
+		if _, ok := value.(string); !ok {
+			continue // 문자열이 아닌 값은 번역하지 않고 소스 값을 그대로 사용
+		}
+		if _, ok := existing[path]; !ok {
+			plan.Pending[path] = true
+			plan.Added++
+			continue
+		}
+		if previous, ok := snapshot[path]; ok && previous != value {
+			plan.Pending[path] = true
+			plan.Modified++
+		}
+	}
+
+	for path := range existing {
+		if _, ok := source[path]; !ok {
+			plan.Removed++
+		}
+	}
+	return plan
+}
+
+// 모든 문자열 키를 번역하는 계획 (증분 모드가 아닐 때 승인된 키를 제외하는 데 사용)
+func planAll(source interface{}) *translationPlan {
+	plan := &translationPlan{
+		Pending:  make(map[string]bool),
+		Existing: make(map[string]interface{}),
+	}
+	walkLeaves(source, "", func(path string, value interface{}) {
+		if _, ok := value.(string); ok {
+			plan.Pending[path] = true
+			plan.Added++
+		}
+	})
+	return plan
+}
+
+// 주어진 값은 번역하지 않고 그대로 유지한다.
+func (p *translationPlan) keep(values map[string]interface{}) {
+	for path, value := range values {
+		delete(p.Pending, path)
+		p.Existing[path] = value
+	}
+}
+
+// 번역이 필요한 키만 남긴 소스 트리
+func (p *translationPlan) pendingContent(source interface{}) interface{} {
+	sourceLeaves := flattenTree(source)
+	content, _ := subTree(source, "", func(path string) (interface{}, bool) {
+		if !p.Pending[path] {
+			return nil, false
+		}
+		return sourceLeaves[path], true
+	})
+	return content
+}
+
+// 소스 구조를 기준으로 새 번역과 기존 번역을 합친다.
+// 소스에서 삭제된 키는 빠지고, 변경되지 않은 키는 기존(사람이 수정했을 수 있는) 값을 유지한다.
+// 번역 응답에서 누락된 키는 결과에서 빠지므로 다음 증분 실행에서 다시 번역된다.
+func (p *translationPlan) merge(source, translated interface{}) interface{} {
+	sourceLeaves := flattenTree(source)
+	translatedLeaves := flattenTree(translated)
+
+	merged, _ := rebuildTree(source, "", func(path string) (interface{}, bool) {
+		if p.Pending[path] {
+			value, ok := translatedLeaves[path]
+			return value, ok
+		}
+		if value, ok := p.Existing[path]; ok {
+			return value, true
+		}
+		return sourceLeaves[path], true
+	})
+	return merged
+}
+
+// 대상 언어별 소스 스냅샷 경로 (<snapshot-dir>/<lang>/<상대 경로>)
+// 언어마다 마지막으로 성공한 번역의 원문을 따로 기록해야 실패한 언어의 변경분을 놓치지 않는다.
+func snapshotPath(snapshotDir, lang, relPath string) string {
+	return filepath.Join(snapshotDir, lang, relPath)
+}
+
+func loadSnapshot(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("error parsing snapshot %s: %v", path, err)
+	}
+	return snapshot, nil
+}
+
+// 번역에 사용한 소스의 평탄화된 리프 값을 스냅샷으로 저장
+func saveSnapshot(path string, source interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating snapshot directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(flattenTree(source), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling snapshot: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// 작업에 대한 증분 번역 계획 수립
+func planJob(job TranslationJob, outDir, snapshotDir string) (*translationPlan, error) {
+	existing, err := readLocaleTree(targetFilePath(outDir, job.TargetLang, job.File), job.TargetLang)
+	if err != nil {
+		return nil, fmt.Errorf("error reading existing translation: %v", err)
+	}
+
+	snapshot, err := loadSnapshot(snapshotPath(snapshotDir, job.TargetLang, job.File))
+	if err != nil {
+		return nil, err
+	}
+
+	existingLeaves := make(map[string]interface{})
+	if existing != nil {
+		existingLeaves = flattenTree(existing)
+	}
+	return planIncremental(flattenTree(job.Content), existingLeaves, snapshot), nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestIncrementalPlanAndMerge(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		existing     string
+		snapshot     string
+		wantPending  string
+		wantMerged   string
+		wantAdded    int
+		wantModified int
+		wantRemoved  int
+	}{
+		{
+			name:        "changed and added keys",
+			source:      `{"a":"A2","b":"B","c":"C"}`,
+			existing:    `{"a":"a","b":"b","old":"o"}`,
+			snapshot:    `{"a":"A","b":"B","old":"O"}`,
+			wantPending: `{"a":"A2","c":"C"}`,
+			wantMerged:  `{"a":"T(A2)","b":"b","c":"T(C)"}`,
+			wantAdded:   1, wantModified: 1, wantRemoved: 1,
+		},
+		{
+			name:         "one element of an array changed",
+			source:       `{"steps":["Open","Go","Stop"],"x":"Hi"}`,
+			existing:     `{"steps":["o","w","s"],"x":"h"}`,
+			snapshot:     `{"steps":["Open","Walk","Stop"],"x":"Hi"}`,
+			wantPending:  `{"steps":{"1":"Go"}}`,
+			wantMerged:   `{"steps":["o","T(Go)","s"],"x":"h"}`,
+			wantModified: 1,
+		},
+		{
+			name:        "element appended to an array",
+			source:      `{"steps":[1,"Go","Stop"]}`,
+			existing:    `{"steps":[1,"g"]}`,
+			snapshot:    `{"steps":[1,"Go"]}`,
+			wantPending: `{"steps":{"2":"Stop"}}`,
+			wantMerged:  `{"steps":[1,"g","T(Stop)"]}`,
+			wantAdded:   1,
+		},
+		{
+			name:        "no snapshot keeps existing translations",
+			source:      `{"a":"A","b":["x","y"]}`,
+			existing:    `{"a":"a","b":["X"]}`,
+			wantPending: `{"b":{"1":"y"}}`,
+			wantMerged:  `{"a":"a","b":["X","T(y)"]}`,
+			wantAdded:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := mustParse(t, tt.source)
+			var snapshot map[string]interface{}
+			if tt.snapshot != "" {
+				snapshot = flattenTree(mustParse(t, tt.snapshot))
+			}
+			plan := planIncremental(flattenTree(source), flattenTree(mustParse(t, tt.existing)), snapshot)
+			if plan.Added != tt.wantAdded || plan.Modified != tt.wantModified || plan.Removed != tt.wantRemoved {
+				t.Errorf("added/modified/removed = %d/%d/%d, want %d/%d/%d", plan.Added, plan.Modified, plan.Removed, tt.wantAdded, tt.wantModified, tt.wantRemoved)
+			}
+
+			pending := plan.pendingContent(source)
+			if got := mustJSON(t, pending); got != tt.wantPending {
+				t.Errorf("pending = %s, want %s", got, tt.wantPending)
+			}
+			if got := mustJSON(t, plan.merge(source, fakeTranslate(pending))); got != tt.wantMerged {
+				t.Errorf("merged = %s, want %s", got, tt.wantMerged)
+			}
+		})
+	}
+}
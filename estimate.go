@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// 요청 없이 계산한 예상 사용량과 비용 (--dry-run)
+type costEstimate struct {
+	Requests     int64
+	PromptTokens int64
+	OutputTokens int64 // 소스 JSON과 같은 길이의 번역이 돌아온다고 가정
+	Characters   int64 // 프롬프트가 없는 백엔드(DeepL, Google)에 보낼 원문 글자 수
+	Cost         float64
+	Unpriced     bool // 가격표에 없는 백엔드가 있음
+	Approximate  bool // 토크나이저 없이 근사치로 셈
+}
+
+func (e *costEstimate) add(other costEstimate) {
+	e.Requests += other.Requests
+	e.PromptTokens += other.PromptTokens
+	e.OutputTokens += other.OutputTokens
+	e.Characters += other.Characters
+	e.Cost += other.Cost
+	e.Unpriced = e.Unpriced || other.Unpriced
+	e.Approximate = e.Approximate || other.Approximate
+}
+
+// 작업 하나의 요청을 실제 실행과 같은 방식(증분 계획, 승인된 키, 번역 메모리, 청크 분할)으로 만들어
+// 프롬프트 토큰 수와 비용을 계산한다. 재시도와 구조 복구 요청은 포함하지 않는다.
+func estimateJob(translator Translator, job TranslationJob, opts *translateOptions) (costEstimate, error) {
+	var estimate costEstimate
+
+	content := job.Content
+	plan, err := planTranslation(job, opts)
+	if err != nil {
+		return estimate, err
+	}
+	if plan != nil {
+		if len(plan.Pending) == 0 {
+			return estimate, nil
+		}
+		content = plan.pendingContent(job.Content)
+	}
+
+	sourceLeaves := flattenTree(content)
+	// 추정만 하므로 번역 메모리의 사용 기록은 바꾸지 않는다
+	if cached := lookupMemory(translator, sourceLeaves, job.SourceLang, job.TargetLang, job.Context, opts, true); len(cached) > 0 {
+		content = withoutLeaves(content, cached)
+	}
+
+	price, priced := priceFor(translator, opts.Pricing)
+	chat, isChat := translator.(*chatTranslator)
+	var counter *tokenCounter
+	if isChat {
+		counter = tokenCounterFor(chat.model, price.Encoding)
+		estimate.Approximate = counter.approximate()
+	}
+
+	for _, paths := range splitIntoChunks(content, opts.chunkBudget()) {
+		chunk := chunkTree(content, sourceLeaves, paths)
+		estimate.Requests++
+		if !isChat {
+			for _, path := range paths {
+				text, _ := sourceLeaves[path].(string)
+				estimate.Characters += int64(utf8.RuneCountInString(text))
+			}
+			continue
+		}
+
+		prompt, err := chat.buildPrompt(chunk, job.SourceLang, job.TargetLang)
+		if err != nil {
+			return estimate, err
+		}
+		payload, err := json.Marshal(chunk)
+		if err != nil {
+			return estimate, fmt.Errorf("JSON 변환 중 오류: %v", err)
+		}
+		estimate.PromptTokens += int64(counter.count(prompt) + CHAT_REQUEST_OVERHEAD_TOKENS)
+		estimate.OutputTokens += int64(counter.count(string(payload)))
+	}
+	estimate.Unpriced = !priced && estimate.Requests > 0
+	estimate.Cost = price.cost(estimate.PromptTokens, estimate.OutputTokens, estimate.Characters)
+	return estimate, nil
+}
+
+// 모든 작업의 예상 사용량을 언어별, 전체로 출력한다. 요청은 보내지 않고 파일도 쓰지 않는다.
+func estimateRun(w io.Writer, jobs []TranslationJob, translators *translatorSet, opts *translateOptions) error {
+	var languages []string
+	byLanguage := make(map[string]*costEstimate)
+	backends := make(map[string]map[string]bool) // 언어 → 사용하는 백엔드 이름
+	unpriced := make(map[string]bool)
+
+	for _, job := range jobs {
+		job = prepareJob(job, opts)
+		translator := translators.forLanguage(job.TargetLang).WithPrompt(opts.prompts.forJob(job)).WithContext(job.Context)
+		estimate, err := estimateJob(translator, job, opts)
+		if err != nil {
+			return fmt.Errorf("%s (%s): %v", job.TargetLang, job.File, err)
+		}
+
+		if byLanguage[job.TargetLang] == nil {
+			languages = append(languages, job.TargetLang)
+			byLanguage[job.TargetLang] = &costEstimate{}
+			backends[job.TargetLang] = make(map[string]bool)
+		}
+		byLanguage[job.TargetLang].add(estimate)
+		backends[job.TargetLang][translator.Name()] = true
+		if estimate.Unpriced {
+			unpriced[translator.Name()] = true
+		}
+	}
+
+	fmt.Fprintf(w, "\nDry run: %d jobs in %d languages, no requests sent\n\n", len(jobs), len(languages))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LANGUAGE\tBACKEND\tREQUESTS\tPROMPT TOKENS\tOUTPUT TOKENS (EST.)\tCOST (USD)")
+	var total costEstimate
+	for _, lang := range languages {
+		estimate := byLanguage[lang]
+		total.add(*estimate)
+		var names []string
+		for name := range backends[lang] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(tw, "%s (%s)\t%s\t%s\n", languageMap[lang], lang, strings.Join(names, ", "), formatEstimate(estimate))
+	}
+	fmt.Fprintf(tw, "Total\t\t%s\n", formatEstimate(&total))
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nEstimates exclude retries and repair requests; output assumes translations as long as the source.")
+	if total.Approximate {
+		fmt.Fprintln(w, "~ token counts are approximate: no tokenizer for the model (see the warning above, or set pricing.<model>.encoding)")
+	}
+	if len(unpriced) > 0 {
+		var names []string
+		for name := range unpriced {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(w, "No price configured for %s: add it under pricing in the config file (costs above exclude it)\n", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+func formatEstimate(e *costEstimate) string {
+	approx := ""
+	if e.Approximate {
+		approx = "~"
+	}
+	prompt := fmt.Sprintf("%s%d", approx, e.PromptTokens)
+	output := fmt.Sprintf("%s%d", approx, e.OutputTokens)
+	switch {
+	case e.Characters > 0 && e.PromptTokens == 0:
+		// 프롬프트가 없는 백엔드만 사용
+		prompt, output = fmt.Sprintf("%d chars", e.Characters), "-"
+	case e.Characters > 0:
+		prompt += fmt.Sprintf(" + %d chars", e.Characters)
+	}
+	cost := fmt.Sprintf("%.4f", e.Cost)
+	if e.Unpriced && e.Cost == 0 {
+		cost = "-"
+	}
+	return fmt.Sprintf("%d\t%s\t%s\t%s", e.Requests, prompt, output, cost)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 번역 대상 네임스페이스 파일
+type SourceFile struct {
+	Path     string          // 디스크 상의 경로
+	RelPath  string          // 소스 로케일 디렉터리 기준 상대 경로 (예: "auth.json", "admin/users.json")
+	Content  interface{}     // 파싱된 키/값 트리 (*orderedMap, "@key" 메타데이터 제외)
+	Context  contextNotes    // 키별 맥락 (파일 안의 주석/메타데이터와 맥락 파일)
+	Document *localeDocument // 대상 파일을 원래 형식으로 쓸 때 틀로 사용하는 소스 문서
+}
+
+// 소스 경로에서 번역할 로케일 파일을 찾는다.
+// 파일이면 해당 파일만, 디렉터리면 하위 디렉터리에서 include glob에 맞고 exclude glob에 맞지 않는 파일을 반환한다.
+// 맥락 파일(*.context.yaml)은 번역 대상에서 제외한다.
+func discoverSourceFiles(source, sourceLang string, include, exclude []string) ([]SourceFile, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("error reading source: %v", err)
+	}
+
+	var paths []string
+	root := filepath.Dir(source)
+	if info.IsDir() {
+		root = source
+		err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(source, path)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if matchAnyGlob(include, rel) && !matchAnyGlob(exclude, rel) && !strings.HasSuffix(rel, ".context.yaml") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error scanning source directory: %v", err)
+		}
+	} else {
+		paths = []string{source}
+	}
+	sort.Strings(paths)
+
+	var files []SourceFile
+	for _, path := range paths {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving path %s: %v", path, err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading source file %s: %v", path, err)
+		}
+
+		doc, err := parseLocaleFile(path, data, sourceLang)
+		if err != nil {
+			return nil, err
+		}
+
+		// 맥락은 모델에만 전달하고 대상 파일에는 쓰지 않는다
+		notes := make(contextNotes)
+		notes.merge(doc.Context)
+		sidecar, err := loadContextSidecar(contextSidecarPath(path))
+		if err != nil {
+			return nil, err
+		}
+		notes.merge(sidecar)
+
+		files = append(files, SourceFile{Path: path, RelPath: rel, Content: doc.Content, Context: notes, Document: doc})
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files matching %s found in %s", strings.Join(include, ", "), source)
+	}
+	return files, nil
+}
+
+func matchAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// "/"로 구분된 경로에 대한 glob 매칭. path.Match 문법에 더해 "**"는 0개 이상의 디렉터리와 매칭된다.
+func matchGlob(pattern, name string) bool {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchGlobSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], name[1:])
+}
+
+// 대상 언어의 출력 파일 경로 (<out-dir>/<lang>/<상대 경로>, gettext 템플릿(.pot)의 번역은 .po로 쓴다)
+func targetFilePath(outDir, lang, relPath string) string {
+	if strings.EqualFold(filepath.Ext(relPath), ".pot") {
+		relPath = strings.TrimSuffix(relPath, filepath.Ext(relPath)) + ".po"
+	}
+	return filepath.Join(outDir, lang, relPath)
+}
+
+// 번역된 트리를 소스 파일과 같은 형식으로 쓴다.
+func writeLocaleFile(path string, doc *localeDocument, content interface{}, lang string) ([]byte, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory: %v", err)
+	}
+
+	data, err := doc.render(content, lang)
+	if err != nil {
+		return nil, fmt.Errorf("error writing %s file: %v", doc.format.Name(), err)
+	}
+
+	// 파일 존재 여부 확인
+	if _, err := os.Stat(path); err == nil {
+		fmt.Printf("Overwriting existing file: %s\n", path)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("error writing file: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 로케일 파일 형식
+// 각 형식은 파일을 같은 키/값 트리(*orderedMap)로 파싱하고, 번역된 트리를 소스 파일을 틀로 삼아
+// 원래 형식으로 다시 쓴다. 주석, 키 순서, 번역하지 않는 항목은 소스 파일에서 그대로 가져온다.
+type localeFormat interface {
+	Name() string
+	// 파일 내용을 트리로 파싱한다. lang은 파일의 언어 (Rails YAML의 최상위 언어 키 등에 사용)
+	Parse(data []byte, lang string) (*localeDocument, error)
+	// 번역된 트리를 대상 언어 파일 내용으로 만든다.
+	Render(doc *localeDocument, content interface{}, lang string) ([]byte, error)
+}
+
+// 기존 대상 파일에서 번역 값을 읽는 방법이 소스 파싱과 다른 형식 (gettext PO의 msgstr 등)
+type targetReader interface {
+	ReadTarget(data []byte, lang string) (interface{}, error)
+}
+
+// 파싱된 로케일 파일
+type localeDocument struct {
+	Content interface{}     // 번역할 키/값 트리 (*orderedMap)
+	Context contextNotes    // 파일 안의 주석과 메타데이터에서 얻은 키별 맥락
+	Plurals map[string]bool // 복수형 범주(one, other 등)를 키로 갖는 객체의 경로
+
+	format localeFormat
+	source interface{} // 형식별 원본 표현 (Render에서 틀로 사용)
+}
+
+func (d *localeDocument) render(content interface{}, lang string) ([]byte, error) {
+	return d.format.Render(d, content, lang)
+}
+
+// 확장자별 형식
+var localeFormats = map[string]localeFormat{
+	".json":        jsonFormat{},
+	".arb":         jsonFormat{arb: true},
+	".yml":         yamlFormat{},
+	".yaml":        yamlFormat{},
+	".po":          poFormat{},
+	".pot":         poFormat{},
+	".xml":         androidFormat{},
+	".strings":     stringsFormat{},
+	".stringsdict": stringsdictFormat{},
+	".xlf":         xliffFormat{},
+	".xliff":       xliffFormat{},
+}
+
+func formatForPath(path string) (localeFormat, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	format, ok := localeFormats[ext]
+	if !ok {
+		return nil, fmt.Errorf("unsupported file format %q: %s", ext, path)
+	}
+	return format, nil
+}
+
+// 로케일 파일 파싱
+func parseLocaleFile(path string, data []byte, lang string) (*localeDocument, error) {
+	format, err := formatForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := format.Parse(data, lang)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	if _, ok := doc.Content.(*orderedMap); !ok {
+		return nil, fmt.Errorf("error parsing %s: top-level value must be an object", path)
+	}
+	if doc.Context == nil {
+		doc.Context = make(contextNotes)
+	}
+	doc.format = format
+	return doc, nil
+}
+
+// 기존 대상 파일을 읽어 번역 값 트리로 반환 (파일이 없으면 nil)
+func readLocaleTree(path, lang string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := formatForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if reader, ok := format.(targetReader); ok {
+		tree, err := reader.ReadTarget(data, lang)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", path, err)
+		}
+		return tree, nil
+	}
+	doc, err := format.Parse(data, lang)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return doc.Content, nil
+}
+
+// 소스 파일 텍스트의 일부 범위를 바꾸거나 지워 대상 파일을 만든다. 범위는 앞에서부터 순서대로 넘겨야 한다.
+type textPatch struct {
+	data   []byte
+	buf    bytes.Buffer
+	cursor int
+}
+
+func (p *textPatch) replace(start, end int, text string) {
+	p.buf.Write(p.data[p.cursor:start])
+	p.buf.WriteString(text)
+	p.cursor = end
+}
+
+// 범위를 지운다. 범위가 줄 전체를 차지하면 들여쓰기와 줄바꿈도 함께 지운다.
+func (p *textPatch) remove(start, end int) {
+	lineStart := start
+	for lineStart > p.cursor && (p.data[lineStart-1] == ' ' || p.data[lineStart-1] == '\t') {
+		lineStart--
+	}
+	if lineStart == 0 || p.data[lineStart-1] == '\n' {
+		for end < len(p.data) && (p.data[end] == ' ' || p.data[end] == '\t' || p.data[end] == '\r') {
+			end++
+		}
+		if end < len(p.data) && p.data[end] == '\n' {
+			end++
+			start = lineStart
+		} else if end == len(p.data) {
+			start = lineStart
+		}
+	}
+	p.replace(start, end, "")
+}
+
+func (p *textPatch) finish() []byte {
+	p.buf.Write(p.data[p.cursor:])
+	return p.buf.Bytes()
+}
+
+// JSON과 Flutter ARB
+// "@key" 메타데이터는 맥락으로 떼어 낸다. (JSON은 "key"가 함께 있는 경우만) ARB는 대상 파일에 메타데이터를 그대로 두고 "@@locale"만 대상 언어로 바꾼다.
+type jsonFormat struct {
+	arb bool
+}
+
+func (f jsonFormat) Name() string {
+	if f.arb {
+		return "arb"
+	}
+	return "json"
+}
+
+func (f jsonFormat) Parse(data []byte, lang string) (*localeDocument, error) {
+	tree, err := parseOrderedJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	notes := make(contextNotes)
+	content := extractKeyMetadata(tree, "", notes, f.arb)
+	return &localeDocument{Content: content, Context: notes, source: tree}, nil
+}
+
+func (f jsonFormat) Render(doc *localeDocument, content interface{}, lang string) ([]byte, error) {
+	source, ok := doc.source.(*orderedMap)
+	translated, isMap := content.(*orderedMap)
+	if !f.arb || !ok || !isMap {
+		return json.MarshalIndent(content, "", "  ")
+	}
+
+	result := newOrderedMap()
+	for _, key := range source.Keys() {
+		switch {
+		case key == "@@locale":
+			result.Set(key, lang)
+		case strings.HasPrefix(key, "@@"):
+			value, _ := source.Get(key)
+			result.Set(key, value)
+		case strings.HasPrefix(key, "@"):
+			// 번역된 메시지의 메타데이터만 남긴다
+			if _, ok := translated.Get(strings.TrimPrefix(key, "@")); ok {
+				value, _ := source.Get(key)
+				result.Set(key, value)
+			}
+		default:
+			if value, ok := translated.Get(key); ok {
+				result.Set(key, value)
+			}
+		}
+	}
+	for _, key := range translated.Keys() {
+		if _, ok := result.Get(key); !ok {
+			value, _ := translated.Get(key)
+			result.Set(key, value)
+		}
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"testing"
+)
+
+// 형식별 왕복 검사: 소스 파싱 → 번역 트리 렌더링 → 대상 파일 다시 읽기
+type formatRoundTrip struct {
+	name        string
+	path        string
+	source      string
+	wantContent string // 소스에서 읽은 트리
+	translated  string
+	want        string // 대상 파일 내용
+	wantRead    string // 대상 파일에서 다시 읽은 번역 (비어 있으면 translated)
+}
+
+func runFormatRoundTrips(t *testing.T, tests []formatRoundTrip) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseLocaleFile(tt.path, []byte(tt.source), "en")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mustJSON(t, doc.Content); got != tt.wantContent {
+				t.Errorf("content = %s, want %s", got, tt.wantContent)
+			}
+
+			data, err := doc.render(mustParse(t, tt.translated), "fr")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("rendered:\n%s\nwant:\n%s", data, tt.want)
+			}
+
+			var read interface{}
+			if reader, ok := doc.format.(targetReader); ok {
+				read, err = reader.ReadTarget(data, "fr")
+			} else {
+				var target *localeDocument
+				if target, err = doc.format.Parse(data, "fr"); err == nil {
+					read = target.Content
+				}
+			}
+			if err != nil {
+				t.Fatalf("reading the target: %v", err)
+			}
+			wantRead := tt.wantRead
+			if wantRead == "" {
+				wantRead = tt.translated
+			}
+			if got, want := mustJSON(t, read), mustJSON(t, mustParse(t, wantRead)); got != want {
+				t.Errorf("read back = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestJSONFormatRoundTrip(t *testing.T) {
+	runFormatRoundTrips(t, []formatRoundTrip{
+		{
+			name:        "nested JSON drops @key metadata",
+			path:        "common.json",
+			source:      `{"a":"Hi","@a":{"description":"greeting"},"n":{"b":"B"},"list":["x","y"]}`,
+			wantContent: `{"a":"Hi","n":{"b":"B"},"list":["x","y"]}`,
+			translated:  `{"a":"Salut","n":{"b":"Bé"},"list":["x fr","y fr"]}`,
+			want:        "{\n  \"a\": \"Salut\",\n  \"n\": {\n    \"b\": \"Bé\"\n  },\n  \"list\": [\n    \"x fr\",\n    \"y fr\"\n  ]\n}",
+		},
+		{
+			name:        "JSON keeps @-prefixed keys without a sibling",
+			path:        "chat.json",
+			source:      `{"@mention":"Mention someone","n":{"@all":"Everyone","b":"B","@b":"bold label"}}`,
+			wantContent: `{"@mention":"Mention someone","n":{"@all":"Everyone","b":"B"}}`,
+			translated:  `{"@mention":"Mentionner quelqu'un","n":{"@all":"Tout le monde","b":"Bé"}}`,
+			want:        "{\n  \"@mention\": \"Mentionner quelqu'un\",\n  \"n\": {\n    \"@all\": \"Tout le monde\",\n    \"b\": \"Bé\"\n  }\n}",
+		},
+		{
+			name:        "ARB keeps metadata of translated messages and sets @@locale",
+			path:        "app_en.arb",
+			source:      `{"@@locale":"en","a":"Hi","@a":{"description":"greeting"},"b":"Bye","@b":{}}`,
+			wantContent: `{"a":"Hi","b":"Bye"}`,
+			translated:  `{"a":"Salut"}`,
+			want:        "{\n  \"@@locale\": \"fr\",\n  \"a\": \"Salut\",\n  \"@a\": {\n    \"description\": \"greeting\"\n  }\n}",
+		},
+	})
+}
+
+func TestJSONFormatContext(t *testing.T) {
+	doc, err := parseLocaleFile("app_en.arb", []byte(`{"a":"Hi","@a":{"description":"greeting"}}`), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Context["/a"].Description; got != "greeting" {
+		t.Errorf("context = %q, want %q", got, "greeting")
+	}
+
+	// ARB에서는 짝이 없는 "@key"도 메타데이터, JSON에서는 짝이 있는 경우만
+	doc, err = parseLocaleFile("app_en.arb", []byte(`{"a":"Hi","@orphan":{"description":"unused"}}`), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustJSON(t, doc.Content); got != `{"a":"Hi"}` {
+		t.Errorf("ARB content = %s", got)
+	}
+	doc, err = parseLocaleFile("chat.json", []byte(`{"@mention":"Mention","b":"B","@b":"bold label"}`), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Context["/b"].Description; got != "bold label" {
+		t.Errorf("context = %q, want %q", got, "bold label")
+	}
+	if _, ok := doc.Context["/mention"]; ok {
+		t.Error("@mention was read as metadata of /mention")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DEFAULT_GLOSSARY_FILE = "glossary.yaml"
+
+// 용어집 항목
+type glossaryTerm struct {
+	Term           string            `yaml:"term"`
+	Translations   map[string]string `yaml:"translations"`   // 언어별 승인된 번역
+	DoNotTranslate bool              `yaml:"doNotTranslate"` // 모든 언어에서 원문 그대로 유지 (제품명 등)
+	CaseSensitive  bool              `yaml:"caseSensitive"`  // 대소문자를 구분해 찾는다
+
+	pattern *regexp.Regexp // 소스 값에서 용어를 찾는 패턴
+}
+
+// 프로젝트 용어집 (glossary.yaml)
+type glossary struct {
+	Terms []*glossaryTerm `yaml:"terms"`
+
+	hash string // 파일 내용 해시 (프롬프트 해시에 포함)
+}
+
+// 용어집 로드
+// 기본 경로의 파일이 없으면 nil을 반환하고, 명시적으로 지정한 파일이 없으면 오류를 반환한다.
+func loadGlossary(path string, explicit bool) (*glossary, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading glossary: %v", err)
+	}
+
+	var g glossary
+	if err := yaml.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("error parsing glossary %s: %v", path, err)
+	}
+	for i, term := range g.Terms {
+		term.Term = strings.TrimSpace(term.Term)
+		if term.Term == "" {
+			return nil, fmt.Errorf("glossary %s: term %d is empty", path, i+1)
+		}
+		if !term.DoNotTranslate && len(term.Translations) == 0 {
+			return nil, fmt.Errorf("glossary %s: term %q needs translations or doNotTranslate", path, term.Term)
+		}
+		term.pattern = termPattern(term.Term, term.CaseSensitive)
+	}
+	g.hash = hashBytes(data)
+	return &g, nil
+}
+
+// 용어가 단어 문자로 시작하거나 끝나면 단어 경계에서만 찾는다. ("App"이 "Application"에 걸리지 않도록)
+func termPattern(term string, caseSensitive bool) *regexp.Regexp {
+	expr := regexp.QuoteMeta(term)
+	if isWordByte(term[0]) {
+		expr = `\b` + expr
+	}
+	if isWordByte(term[len(term)-1]) {
+		expr += `\b`
+	}
+	if !caseSensitive {
+		expr = `(?i)` + expr
+	}
+	return regexp.MustCompile(expr)
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// 대상 언어에 적용되는 용어의 기대 형태 (원문 유지 또는 승인된 번역)
+func (t *glossaryTerm) expected(targetLang string) (string, bool) {
+	if t.DoNotTranslate {
+		return t.Term, true
+	}
+	translation, ok := t.Translations[targetLang]
+	return translation, ok && translation != ""
+}
+
+func (t *glossaryTerm) appearsIn(text string) bool {
+	return t.pattern.MatchString(text)
+}
+
+func containsTerm(text, term string, caseSensitive bool) bool {
+	if caseSensitive {
+		return strings.Contains(text, term)
+	}
+	return strings.Contains(strings.ToLower(text), strings.ToLower(term))
+}
+
+// 소스 값들에 등장하고 대상 언어에 적용되는 용어 (용어 순서 유지)
+func (g *glossary) termsIn(texts []string, targetLang string) []*glossaryTerm {
+	if g == nil {
+		return nil
+	}
+	var terms []*glossaryTerm
+	for _, term := range g.Terms {
+		if _, ok := term.expected(targetLang); !ok {
+			continue
+		}
+		for _, text := range texts {
+			if term.appearsIn(text) {
+				terms = append(terms, term)
+				break
+			}
+		}
+	}
+	return terms
+}
+
+// 프롬프트 템플릿에 넘길 용어집 항목
+func promptGlossary(terms []*glossaryTerm, targetLang string) []promptGlossaryEntry {
+	var entries []promptGlossaryEntry
+	for _, term := range terms {
+		translation, _ := term.expected(targetLang)
+		entries = append(entries, promptGlossaryEntry{Term: term.Term, Translation: translation, DoNotTranslate: term.DoNotTranslate})
+	}
+	return entries
+}
+
+// 번역 값이 용어집을 지키는지 확인한다. 소스에 용어가 있는데 번역에 기대 형태가 없으면 문제로 보고한다.
+func (g *glossary) check(source, translated, targetLang string) []string {
+	if g == nil {
+		return nil
+	}
+	var problems []string
+	for _, term := range g.Terms {
+		want, ok := term.expected(targetLang)
+		if !ok || !term.appearsIn(source) || containsTerm(translated, want, term.CaseSensitive) {
+			continue
+		}
+		if term.DoNotTranslate {
+			problems = append(problems, fmt.Sprintf("glossary term %q was translated", term.Term))
+		} else {
+			problems = append(problems, fmt.Sprintf("glossary term %q not rendered as %q", term.Term, want))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+func (g *glossary) Hash() string {
+	if g == nil {
+		return ""
+	}
+	return g.hash
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTermPattern(t *testing.T) {
+	tests := []struct {
+		name          string
+		term          string
+		caseSensitive bool
+		text          string
+		want          bool
+	}{
+		{name: "whole word", term: "App", text: "Open the App now", want: true},
+		{name: "not inside a longer word", term: "App", text: "Application settings", want: false},
+		{name: "punctuation is a boundary", term: "App", text: "the App's icon", want: true},
+		{name: "case-insensitive by default", term: "App", text: "open the app", want: true},
+		{name: "case-sensitive", term: "App", caseSensitive: true, text: "open the app", want: false},
+		{name: "Latin term next to CJK text", term: "Pro", text: "Pro플랜으로 업그레이드", want: true},
+		{name: "Latin term inside Latin text next to CJK", term: "Pro", text: "Process를 시작", want: false},
+		{name: "CJK term inside CJK text", term: "로그인", text: "로그인하기", want: true},
+		{name: "Japanese term without spaces", term: "設定", text: "アカウント設定を開く", want: true},
+		{name: "term ending in a symbol", term: "C++", text: "Learn C++ today", want: true},
+		{name: "term ending in a symbol, Latin prefix", term: "C++", text: "ObjC++", want: false},
+		{name: "multi-word term", term: "sign in", text: "Please Sign In again", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := termPattern(tt.term, tt.caseSensitive).MatchString(tt.text); got != tt.want {
+				t.Errorf("%q in %q = %v, want %v", tt.term, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func testGlossary(terms ...*glossaryTerm) *glossary {
+	for _, term := range terms {
+		term.pattern = termPattern(term.Term, term.CaseSensitive)
+	}
+	return &glossary{Terms: terms}
+}
+
+func TestGlossaryCheck(t *testing.T) {
+	g := testGlossary(
+		&glossaryTerm{Term: "Acme Cloud", DoNotTranslate: true},
+		&glossaryTerm{Term: "workspace", Translations: map[string]string{"fr": "espace de travail", "ko": "워크스페이스"}},
+		&glossaryTerm{Term: "Pro", CaseSensitive: true, Translations: map[string]string{"fr": "Pro"}},
+	)
+	tests := []struct {
+		name       string
+		source     string
+		translated string
+		lang       string
+		want       []string
+	}{
+		{name: "terms kept", source: "Acme Cloud workspace", translated: "Espace de travail Acme Cloud", lang: "fr"},
+		{name: "do-not-translate term translated", source: "Welcome to Acme Cloud", translated: "Bienvenue sur Acme Nuage", lang: "fr", want: []string{`glossary term "Acme Cloud" was translated`}},
+		{name: "do-not-translate applies to every language", source: "Acme Cloud", translated: "Acme 클라우드", lang: "ja", want: []string{`glossary term "Acme Cloud" was translated`}},
+		{name: "approved translation missing", source: "New workspace", translated: "Nouvel espace", lang: "fr", want: []string{`glossary term "workspace" not rendered as "espace de travail"`}},
+		{name: "approved translation in CJK text", source: "Open your Workspace", translated: "워크스페이스를 엽니다", lang: "ko"},
+		{name: "language without a translation", source: "New workspace", translated: "Neuer Arbeitsbereich", lang: "de"},
+		{name: "term not in the source", source: "Applications", translated: "Anwendungen", lang: "fr"},
+		{name: "case-sensitive term not matched", source: "a pro tip", translated: "un conseil", lang: "fr"},
+		{name: "case-sensitive translation", source: "Go Pro", translated: "Passez en pro", lang: "fr", want: []string{`glossary term "Pro" not rendered as "Pro"`}},
+		{
+			name: "problems are sorted", source: "Acme Cloud workspace", translated: "Nuage", lang: "fr",
+			want: []string{`glossary term "Acme Cloud" was translated`, `glossary term "workspace" not rendered as "espace de travail"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.check(tt.source, tt.translated, tt.lang); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var none *glossary
+	if got := none.check("Acme Cloud", "", "fr"); got != nil {
+		t.Errorf("nil glossary: %v", got)
+	}
+}
+
+func TestGlossaryTermsIn(t *testing.T) {
+	g := testGlossary(
+		&glossaryTerm{Term: "Acme", DoNotTranslate: true},
+		&glossaryTerm{Term: "workspace", Translations: map[string]string{"fr": "espace de travail"}},
+		&glossaryTerm{Term: "invoice", Translations: map[string]string{"fr": "facture"}},
+	)
+	var got []string
+	for _, term := range g.termsIn([]string{"Your workspace", "Acme billing"}, "fr") {
+		got = append(got, term.Term)
+	}
+	if want := []string{"Acme", "workspace"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fr terms = %v, want %v", got, want)
+	}
+	got = nil
+	for _, term := range g.termsIn([]string{"Your workspace", "Acme billing"}, "de") {
+		got = append(got, term.Term)
+	}
+	if want := []string{"Acme"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("de terms = %v, want %v", got, want)
+	}
+}
+
+func TestLoadGlossary(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid", content: "terms:\n  - term: ' Acme '\n    doNotTranslate: true\n  - term: workspace\n    translations: {fr: espace de travail}\n"},
+		{name: "empty term", content: "terms:\n  - term: ' '\n    doNotTranslate: true\n", wantErr: true},
+		{name: "term without translations", content: "terms:\n  - term: workspace\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			g, err := loadGlossary(path, true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && (g.Terms[0].Term != "Acme" || g.Hash() == "" || !g.Terms[0].appearsIn("Acme Cloud")) {
+				t.Errorf("terms %+v, hash %q", g.Terms, g.Hash())
+			}
+		})
+	}
+
+	if g, err := loadGlossary(filepath.Join(dir, "missing.yaml"), false); g != nil || err != nil {
+		t.Errorf("missing default glossary = %v, %v, want nil", g, err)
+	}
+	if _, err := loadGlossary(filepath.Join(dir, "missing.yaml"), true); err == nil {
+		t.Error("missing explicit glossary: want an error")
+	}
+}
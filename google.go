@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	GOOGLE_TRANSLATE_API_URL = "https://translation.googleapis.com/language/translate/v2"
+	GOOGLE_BATCH_SIZE        = 100 // 요청당 최대 텍스트 수 (API 한도 128)
+)
+
+// Google Cloud Translation v2 REST API 기반 백엔드
+type googleTranslator struct {
+	apiKey     string
+	apiURL     string
+	httpClient *http.Client
+	limiter    *rateLimiter // 분당 요청 한도 (배치 요청마다 적용)
+}
+
+func newGoogleTranslator(apiKey, apiURL string, limiter *rateLimiter) *googleTranslator {
+	if apiURL == "" {
+		apiURL = GOOGLE_TRANSLATE_API_URL
+	}
+	return &googleTranslator{
+		apiKey:     apiKey,
+		apiURL:     apiURL,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		limiter:    limiter,
+	}
+}
+
+func (t *googleTranslator) Name() string {
+	return "google"
+}
+
+func (t *googleTranslator) PromptHash() string {
+	return ""
+}
+
+func (t *googleTranslator) WithPrompt(*promptTemplate) Translator {
+	return t
+}
+
+func (t *googleTranslator) WithContext(contextNotes) Translator {
+	return t
+}
+
+// Google 언어 코드 (languageMap 코드와 다른 경우만 변환)
+func googleLanguageCode(lang string) string {
+	switch lang {
+	case "fil":
+		return "tl"
+	case "zh":
+		return "zh-CN"
+	}
+	return lang
+}
+
+func (t *googleTranslator) Translate(ctx context.Context, content interface{}, sourceLang, targetLang string) (interface{}, error) {
+	return translateLeaves(ctx, content, GOOGLE_BATCH_SIZE, func(texts []string) ([]string, error) {
+		if err := t.limiter.wait(ctx, 0); err != nil {
+			return nil, err
+		}
+		body, err := json.Marshal(map[string]interface{}{
+			"q":      texts,
+			"source": googleLanguageCode(sourceLang),
+			"target": googleLanguageCode(targetLang),
+			"format": "html",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint := t.apiURL + "?key=" + url.QueryEscape(t.apiKey)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := t.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("google translate request error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("google translate response error: %v", err)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			t.limiter.pause(parseRetryAfter(resp.Header))
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, &providerError{
+				StatusCode: resp.StatusCode,
+				RetryAfter: parseRetryAfter(resp.Header),
+				Err:        fmt.Errorf("google translate error: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data))),
+			}
+		}
+
+		var result struct {
+			Data struct {
+				Translations []struct {
+					TranslatedText string `json:"translatedText"`
+				} `json:"translations"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("google translate response parsing error: %v", err)
+		}
+
+		translated := make([]string, len(result.Data.Translations))
+		for i, item := range result.Data.Translations {
+			// HTML 모드 응답은 &quot; 등의 엔티티가 이스케이프되어 돌아온다
+			translated[i] = html.UnescapeString(item.TranslatedText)
+		}
+		return translated, nil
+	})
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CLDR 복수형 범주 (languageMap의 언어, 기수/서수)
+// 표에 없는 언어는 "other"만 요구한다.
+var pluralCategories = map[string]struct{ Cardinal, Ordinal []string }{
+	"af":  {[]string{"one", "other"}, []string{"other"}},
+	"am":  {[]string{"one", "other"}, []string{"other"}},
+	"ar":  {[]string{"zero", "one", "two", "few", "many", "other"}, []string{"other"}},
+	"bg":  {[]string{"one", "other"}, []string{"other"}},
+	"bn":  {[]string{"one", "other"}, []string{"one", "two", "few", "many", "other"}},
+	"ca":  {[]string{"one", "many", "other"}, []string{"one", "two", "few", "other"}},
+	"cs":  {[]string{"one", "few", "many", "other"}, []string{"other"}},
+	"da":  {[]string{"one", "other"}, []string{"other"}},
+	"de":  {[]string{"one", "other"}, []string{"other"}},
+	"el":  {[]string{"one", "other"}, []string{"other"}},
+	"en":  {[]string{"one", "other"}, []string{"one", "two", "few", "other"}},
+	"es":  {[]string{"one", "many", "other"}, []string{"other"}},
+	"et":  {[]string{"one", "other"}, []string{"other"}},
+	"fa":  {[]string{"one", "other"}, []string{"other"}},
+	"fi":  {[]string{"one", "other"}, []string{"other"}},
+	"fil": {[]string{"one", "other"}, []string{"one", "other"}},
+	"fr":  {[]string{"one", "many", "other"}, []string{"one", "other"}},
+	"he":  {[]string{"one", "two", "other"}, []string{"other"}},
+	"hi":  {[]string{"one", "other"}, []string{"one", "two", "few", "many", "other"}},
+	"hr":  {[]string{"one", "few", "other"}, []string{"other"}},
+	"hu":  {[]string{"one", "other"}, []string{"one", "other"}},
+	"id":  {[]string{"other"}, []string{"other"}},
+	"is":  {[]string{"one", "other"}, []string{"other"}},
+	"it":  {[]string{"one", "many", "other"}, []string{"many", "other"}},
+	"ja":  {[]string{"other"}, []string{"other"}},
+	"ka":  {[]string{"one", "other"}, []string{"one", "many", "other"}},
+	"km":  {[]string{"other"}, []string{"other"}},
+	"ko":  {[]string{"other"}, []string{"other"}},
+	"lo":  {[]string{"other"}, []string{"one", "other"}},
+	"lt":  {[]string{"one", "few", "many", "other"}, []string{"other"}},
+	"lv":  {[]string{"zero", "one", "other"}, []string{"other"}},
+	"ms":  {[]string{"other"}, []string{"one", "other"}},
+	"my":  {[]string{"other"}, []string{"other"}},
+	"nl":  {[]string{"one", "other"}, []string{"other"}},
+	"no":  {[]string{"one", "other"}, []string{"other"}},
+	"pl":  {[]string{"one", "few", "many", "other"}, []string{"other"}},
+	"pt":  {[]string{"one", "many", "other"}, []string{"other"}},
+	"ro":  {[]string{"one", "few", "other"}, []string{"one", "other"}},
+	"ru":  {[]string{"one", "few", "many", "other"}, []string{"other"}},
+	"si":  {[]string{"one", "other"}, []string{"other"}},
+	"sk":  {[]string{"one", "few", "many", "other"}, []string{"other"}},
+	"sv":  {[]string{"one", "other"}, []string{"one", "other"}},
+	"ta":  {[]string{"one", "other"}, []string{"other"}},
+	"te":  {[]string{"one", "other"}, []string{"other"}},
+	"th":  {[]string{"other"}, []string{"other"}},
+	"tr":  {[]string{"one", "other"}, []string{"other"}},
+	"uk":  {[]string{"one", "few", "many", "other"}, []string{"few", "other"}},
+	"ur":  {[]string{"one", "other"}, []string{"other"}},
+	"vi":  {[]string{"other"}, []string{"one", "other"}},
+	"zh":  {[]string{"other"}, []string{"other"}},
+}
+
+// 대상 언어의 기수(plural)/서수(selectordinal) 범주
+func cldrPluralCategories(lang string, ordinal bool) []string {
+	categories, ok := pluralCategories[lang]
+	if !ok {
+		return []string{"other"}
+	}
+	if ordinal {
+		return categories.Ordinal
+	}
+	return categories.Cardinal
+}
+
+var validPluralCategory = map[string]bool{"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true}
+
+// plural/select/selectordinal 인자가 있는 ICU 메시지 ({name}만 있는 문자열은 플레이스홀더 검사로 충분)
+var icuComplexPattern = regexp.MustCompile(`\{\s*[\w.]+\s*,\s*(?:plural|selectordinal|select)\s*,`)
+
+func isICUMessage(text string) bool {
+	return icuComplexPattern.MatchString(text)
+}
+
+// ICU 메시지 구문 트리의 인자
+type icuArgument struct {
+	Name    string
+	Type    string // "" (단순 인자), number, date, plural, select, selectordinal 등
+	Options []icuOption
+}
+
+// plural/select의 선택지 (selector는 범주, "=N" 또는 select 키)
+type icuOption struct {
+	Selector string
+	Message  []icuArgument // 선택지 안의 인자
+}
+
+type icuParser struct {
+	text []rune
+	pos  int
+}
+
+// ICU 메시지를 파싱해 최상위 인자 목록을 반환한다. 리터럴 텍스트는 버린다.
+func parseICUMessage(text string) ([]icuArgument, error) {
+	p := &icuParser{text: []rune(text)}
+	args, err := p.message(0, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.text) {
+		return nil, fmt.Errorf("unmatched '}' at offset %d", p.pos)
+	}
+	return args, nil
+}
+
+// depth > 0이면 닫는 '}' 앞에서 멈춘다.
+func (p *icuParser) message(depth int, inPlural bool) ([]icuArgument, error) {
+	var args []icuArgument
+	for p.pos < len(p.text) {
+		switch c := p.text[p.pos]; c {
+		case '\'':
+			p.quoted(inPlural)
+		case '{':
+			p.pos++
+			arg, err := p.argument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		case '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("unmatched '}' at offset %d", p.pos)
+			}
+			return args, nil
+		default:
+			p.pos++
+		}
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("unclosed '{'")
+	}
+	return args, nil
+}
+
+// 아포스트로피: 두 개 연속이면 리터럴 아포스트로피, 구문 문자 앞이면 다음 아포스트로피까지 인용, 그 밖에는 리터럴
+func (p *icuParser) quoted(inPlural bool) {
+	p.pos++
+	if p.pos >= len(p.text) {
+		return
+	}
+	next := p.text[p.pos]
+	if next == '\'' {
+		p.pos++
+		return
+	}
+	if next != '{' && next != '}' && !(inPlural && next == '#') {
+		return
+	}
+	for p.pos < len(p.text) {
+		if p.text[p.pos] == '\'' {
+			if p.pos+1 < len(p.text) && p.text[p.pos+1] == '\'' {
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *icuParser) skipSpace() {
+	for p.pos < len(p.text) && unicode.IsSpace(p.text[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *icuParser) word() string {
+	start := p.pos
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if unicode.IsSpace(c) || c == ',' || c == '{' || c == '}' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.text[start:p.pos])
+}
+
+func (p *icuParser) expect(c rune) error {
+	p.skipSpace()
+	if p.pos >= len(p.text) {
+		return fmt.Errorf("expected '%c', got end of message", c)
+	}
+	if p.text[p.pos] != c {
+		return fmt.Errorf("expected '%c' at offset %d, got '%c'", c, p.pos, p.text[p.pos])
+	}
+	p.pos++
+	return nil
+}
+
+// '{' 다음부터 닫는 '}'까지
+func (p *icuParser) argument(inPlural bool) (icuArgument, error) {
+	var arg icuArgument
+	p.skipSpace()
+	arg.Name = p.word()
+	if arg.Name == "" {
+		return arg, fmt.Errorf("empty argument name at offset %d", p.pos)
+	}
+	p.skipSpace()
+	if p.pos < len(p.text) && p.text[p.pos] == '}' {
+		p.pos++
+		return arg, nil
+	}
+	if err := p.expect(','); err != nil {
+		return arg, err
+	}
+	p.skipSpace()
+	arg.Type = p.word()
+	if arg.Type == "" {
+		return arg, fmt.Errorf("missing type for argument {%s}", arg.Name)
+	}
+	p.skipSpace()
+	if p.pos < len(p.text) && p.text[p.pos] == '}' {
+		p.pos++
+		return arg, nil
+	}
+	if err := p.expect(','); err != nil {
+		return arg, err
+	}
+
+	switch arg.Type {
+	case "plural", "selectordinal", "select":
+		if err := p.options(&arg, inPlural || arg.Type != "select"); err != nil {
+			return arg, err
+		}
+	default:
+		// number, date, time 등의 스타일: 중첩된 중괄호까지 건너뛴다
+		for depth := 0; p.pos < len(p.text); p.pos++ {
+			switch p.text[p.pos] {
+			case '{':
+				depth++
+			case '}':
+				if depth == 0 {
+					p.pos++
+					return arg, nil
+				}
+				depth--
+			}
+		}
+		return arg, fmt.Errorf("unclosed argument {%s}", arg.Name)
+	}
+	return arg, nil
+}
+
+func (p *icuParser) options(arg *icuArgument, inPlural bool) error {
+	p.skipSpace()
+	if arg.Type != "select" && strings.HasPrefix(string(p.text[p.pos:]), "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		p.word()
+	}
+
+	seen := make(map[string]bool)
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.text) {
+			return fmt.Errorf("unclosed %s argument {%s}", arg.Type, arg.Name)
+		}
+		if p.text[p.pos] == '}' {
+			p.pos++
+			break
+		}
+		selector := p.word()
+		if selector == "" {
+			return fmt.Errorf("missing selector in {%s, %s} at offset %d", arg.Name, arg.Type, p.pos)
+		}
+		if seen[selector] {
+			return fmt.Errorf("duplicate selector %q in {%s, %s}", selector, arg.Name, arg.Type)
+		}
+		seen[selector] = true
+		if err := p.expect('{'); err != nil {
+			return fmt.Errorf("selector %q of {%s}: %v", selector, arg.Name, err)
+		}
+		message, err := p.message(1, inPlural)
+		if err != nil {
+			return err
+		}
+		p.pos++ // '}'
+		arg.Options = append(arg.Options, icuOption{Selector: selector, Message: message})
+	}
+
+	if !seen["other"] {
+		return fmt.Errorf("{%s, %s} has no 'other' option", arg.Name, arg.Type)
+	}
+	return nil
+}
+
+// 메시지 안의 모든 인자 (선택지 안의 인자 포함)
+func collectICUArguments(args []icuArgument, visit func(icuArgument)) {
+	for _, arg := range args {
+		visit(arg)
+		for _, option := range arg.Options {
+			collectICUArguments(option.Message, visit)
+		}
+	}
+}
+
+// 소스와 번역의 ICU 메시지를 비교한다.
+// 번역이 올바른 구문인지, 인자 이름과 종류가 그대로인지, plural/selectordinal에 대상 언어의
+// CLDR 범주가 모두 있는지, select에 소스의 선택지가 모두 있는지 확인한다.
+func checkICUMessage(source, translated, targetLang string) []string {
+	sourceArgs, err := parseICUMessage(source)
+	if err != nil {
+		return nil // 소스 자체가 ICU 구문이 아니면 검사하지 않음
+	}
+	translatedArgs, err := parseICUMessage(translated)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid ICU message: %v", err)}
+	}
+
+	sourceTypes := make(map[string]string)
+	sourceSelectors := make(map[string]map[string]bool)
+	collectICUArguments(sourceArgs, func(arg icuArgument) {
+		if _, ok := sourceTypes[arg.Name]; !ok {
+			sourceTypes[arg.Name] = arg.Type
+		}
+		if arg.Type == "select" {
+			if sourceSelectors[arg.Name] == nil {
+				sourceSelectors[arg.Name] = make(map[string]bool)
+			}
+			for _, option := range arg.Options {
+				sourceSelectors[arg.Name][option.Selector] = true
+			}
+		}
+	})
+
+	var problems []string
+	translatedTypes := make(map[string]string)
+	collectICUArguments(translatedArgs, func(arg icuArgument) {
+		if _, ok := translatedTypes[arg.Name]; !ok {
+			translatedTypes[arg.Name] = arg.Type
+		}
+		selectors := make(map[string]bool)
+		for _, option := range arg.Options {
+			selectors[option.Selector] = true
+		}
+
+		switch arg.Type {
+		case "plural", "selectordinal":
+			for _, option := range arg.Options {
+				if !validPluralCategory[option.Selector] && !strings.HasPrefix(option.Selector, "=") {
+					problems = append(problems, fmt.Sprintf("ICU %s {%s} has unknown category %q", arg.Type, arg.Name, option.Selector))
+				}
+			}
+			for _, category := range cldrPluralCategories(targetLang, arg.Type == "selectordinal") {
+				if !selectors[category] {
+					problems = append(problems, fmt.Sprintf("ICU %s {%s} missing category %s", arg.Type, arg.Name, category))
+				}
+			}
+		case "select":
+			for selector := range sourceSelectors[arg.Name] {
+				if !selectors[selector] {
+					problems = append(problems, fmt.Sprintf("ICU select {%s} missing option %s", arg.Name, selector))
+				}
+			}
+		}
+	})
+
+	for name, typ := range sourceTypes {
+		got, ok := translatedTypes[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("missing ICU argument {%s}", name))
+		case got != typ:
+			problems = append(problems, fmt.Sprintf("ICU argument {%s} changed from %q to %q", name, typ, got))
+		}
+	}
+	for name := range translatedTypes {
+		if _, ok := sourceTypes[name]; !ok {
+			problems = append(problems, fmt.Sprintf("unexpected ICU argument {%s}", name))
+		}
+	}
+
+	sort.Strings(problems)
+	return dedupeStrings(problems)
+}
+
+// 정렬된 목록에서 연속 중복 제거
+func dedupeStrings(items []string) []string {
+	var result []string
+	for i, item := range items {
+		if i == 0 || item != items[i-1] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckICUMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		translated string
+		lang       string
+		want       []string
+	}{
+		{"polish plural categories", "{n, plural, one {# file} other {# files}}", "{n, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}", "pl", nil},
+		{"missing polish categories", "{n, plural, one {# file} other {# files}}", "{n, plural, one {# plik} other {# pliku}}", "pl", []string{"ICU plural {n} missing category few", "ICU plural {n} missing category many"}},
+		{"japanese needs only other", "{n, plural, one {# file} other {# files}}", "{n, plural, other {#ファイル}}", "ja", nil},
+		{"exact matches are allowed", "{n, plural, =0 {none} one {# file} other {# files}}", "{n, plural, =0 {brak} one {# plik} few {# pliki} many {# plików} other {# pliku}}", "pl", nil},
+		{"unknown category", "{n, plural, one {# file} other {# files}}", "{n, plural, one {# Datei} other {# Dateien} several {#}}", "de", []string{`ICU plural {n} has unknown category "several"`}},
+		{"select options kept", "{g, select, male {He} female {She} other {They}}", "{g, select, male {On} female {Ona} other {Oni}}", "pl", nil},
+		{"select option dropped", "{g, select, male {He} female {She} other {They}}", "{g, select, male {On} other {Oni}}", "pl", []string{"ICU select {g} missing option female"}},
+		{"renamed argument", "{n, plural, one {# file} other {# files}}", "{count, plural, one {# Datei} other {# Dateien}}", "de", []string{"missing ICU argument {n}", "unexpected ICU argument {count}"}},
+		{"ordinal categories", "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", "{n, selectordinal, other {#.}}", "de", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkICUMessage(tt.source, tt.translated, tt.lang)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if problems := checkICUMessage("{n, plural, one {#} other {#}}", "{n, plural, one {#} other {#}", "de"); len(problems) != 1 || !strings.HasPrefix(problems[0], "invalid ICU message") {
+		t.Errorf("unbalanced braces: got %v", problems)
+	}
+}
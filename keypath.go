@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 키 경로는 JSON Pointer(RFC 6901) 형식으로 표현한다. (예: "/nav/home", "/steps/0")
+// 키에 "."이 들어가는 플랫 키 스타일("errors.required")도 구분할 수 있다.
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func joinPointer(parent, token string) string {
+	return parent + "/" + pointerEscaper.Replace(token)
+}
+
+// 트리의 모든 리프 값(문자열, 숫자, 불리언, null)을 키 순서대로 경로와 함께 순회한다.
+func walkLeaves(node interface{}, path string, fn func(path string, value interface{})) {
+	switch v := node.(type) {
+	case *orderedMap:
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			walkLeaves(child, joinPointer(path, key), fn)
+		}
+	case []interface{}:
+		for i, child := range v {
+			walkLeaves(child, joinPointer(path, strconv.Itoa(i)), fn)
+		}
+	default:
+		fn(path, v)
+	}
+}
+
+// 트리를 경로 → 리프 값 맵으로 평탄화
+func flattenTree(node interface{}) map[string]interface{} {
+	leaves := make(map[string]interface{})
+	walkLeaves(node, "", func(path string, value interface{}) {
+		leaves[path] = value
+	})
+	return leaves
+}
+
+// template 트리의 구조를 그대로 따라가며 lookup이 돌려준 리프 값으로 새 트리를 만든다.
+// lookup이 false를 반환한 리프는 결과에서 빠지고, 자식이 모두 빠진 객체/배열도 함께 빠진다.
+// 배열은 원소의 인덱스가 밀리지 않도록 처음 빠진 원소 앞까지만 남긴다 (뒤의 원소는 다음 실행에서 다시 요청된다).
+func rebuildTree(template interface{}, path string, lookup func(path string) (interface{}, bool)) (interface{}, bool) {
+	switch v := template.(type) {
+	case *orderedMap:
+		result := newOrderedMap()
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			if value, ok := rebuildTree(child, joinPointer(path, key), lookup); ok {
+				result.Set(key, value)
+			}
+		}
+		return result, result.Len() > 0 || v.Len() == 0
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for i, child := range v {
+			value, ok := rebuildTree(child, joinPointer(path, strconv.Itoa(i)), lookup)
+			if !ok {
+				break
+			}
+			result = append(result, value)
+		}
+		return result, len(result) > 0 || len(v) == 0
+	default:
+		return lookup(path)
+	}
+}
+
+// 번역 요청에 보낼 부분 트리 (청크, 증분 모드의 변경분, 번역 메모리에 없는 키 등)
+// rebuildTree와 같지만 일부 원소만 남은 배열은 원래 인덱스를 키로 하는 객체가 된다.
+// 그래서 부분 트리의 경로("/steps/2")가 원래 트리의 경로와 같고, 번역 결과를 경로로 합칠 수 있다.
+func subTree(template interface{}, path string, lookup func(path string) (interface{}, bool)) (interface{}, bool) {
+	switch v := template.(type) {
+	case *orderedMap:
+		result := newOrderedMap()
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			if value, ok := subTree(child, joinPointer(path, key), lookup); ok {
+				result.Set(key, value)
+			}
+		}
+		return result, result.Len() > 0 || v.Len() == 0
+	case []interface{}:
+		kept := newOrderedMap()
+		for i, child := range v {
+			if value, ok := subTree(child, joinPointer(path, strconv.Itoa(i)), lookup); ok {
+				kept.Set(strconv.Itoa(i), value)
+			}
+		}
+		if kept.Len() < len(v) {
+			return kept, kept.Len() > 0
+		}
+		result := make([]interface{}, 0, len(v))
+		for _, key := range kept.Keys() {
+			value, _ := kept.Get(key)
+			result = append(result, value)
+		}
+		return result, true
+	default:
+		return lookup(path)
+	}
+}
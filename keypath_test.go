@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mustParse(t *testing.T, text string) interface{} {
+	t.Helper()
+	tree, err := parseOrderedJSON([]byte(text))
+	if err != nil {
+		t.Fatalf("parse %s: %v", text, err)
+	}
+	return tree
+}
+
+func mustJSON(t *testing.T, tree interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data)
+}
+
+// 문자열 리프를 모두 fn으로 바꾼 트리 (가짜 번역에 사용)
+func mapStrings(node interface{}, fn func(string) string) interface{} {
+	switch v := node.(type) {
+	case *orderedMap:
+		result := newOrderedMap()
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			result.Set(key, mapStrings(child, fn))
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, child := range v {
+			result[i] = mapStrings(child, fn)
+		}
+		return result
+	case string:
+		return fn(v)
+	default:
+		return v
+	}
+}
+
+func fakeTranslate(node interface{}) interface{} {
+	return mapStrings(node, func(text string) string { return "T(" + text + ")" })
+}
+
+func TestRebuildTreeKeepsArrayIndices(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		missing []string
+		want    string
+	}{
+		{"all kept", `{"a":["x","y"],"b":"z"}`, nil, `{"a":["x","y"],"b":"z"}`},
+		{"object key dropped", `{"a":"x","b":"y"}`, []string{"/a"}, `{"b":"y"}`},
+		{"array truncated at the first gap", `{"a":["x","y","z"]}`, []string{"/a/1"}, `{"a":["x"]}`},
+		{"array emptied is dropped", `{"a":["x"],"b":"y"}`, []string{"/a/0"}, `{"b":"y"}`},
+		{"empty array kept", `{"a":[],"b":"y"}`, nil, `{"a":[],"b":"y"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := mustParse(t, tt.source)
+			leaves := flattenTree(source)
+			for _, path := range tt.missing {
+				delete(leaves, path)
+			}
+			got, _ := rebuildTree(source, "", func(path string) (interface{}, bool) {
+				value, ok := leaves[path]
+				return value, ok
+			})
+			if mustJSON(t, got) != tt.want {
+				t.Errorf("got %s, want %s", mustJSON(t, got), tt.want)
+			}
+		})
+	}
+}
+
+func TestSubTreeKeepsPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		keep   []string
+		want   string
+	}{
+		{"whole array stays an array", `{"a":["x","y"],"b":"z"}`, []string{"/a/0", "/a/1"}, `{"a":["x","y"]}`},
+		{"partial array becomes an index-keyed object", `{"steps":[1,"Go","Stop"],"x":"Hi"}`, []string{"/steps/1", "/steps/2", "/x"}, `{"steps":{"1":"Go","2":"Stop"},"x":"Hi"}`},
+		{"nested arrays", `{"a":[["p","q"],["r","s"]]}`, []string{"/a/1/1"}, `{"a":{"1":{"1":"s"}}}`},
+		{"objects inside arrays", `{"a":[{"t":"x"},{"t":"y"}]}`, []string{"/a/1/t"}, `{"a":{"1":{"t":"y"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := mustParse(t, tt.source)
+			leaves := flattenTree(source)
+			keep := make(map[string]bool)
+			for _, path := range tt.keep {
+				keep[path] = true
+			}
+			got, _ := subTree(source, "", func(path string) (interface{}, bool) {
+				return leaves[path], keep[path]
+			})
+			if mustJSON(t, got) != tt.want {
+				t.Errorf("got %s, want %s", mustJSON(t, got), tt.want)
+			}
+			// 부분 트리의 경로와 값은 원래 트리와 같다
+			for path, value := range flattenTree(got) {
+				if !keep[path] || leaves[path] != value {
+					t.Errorf("path %s = %v does not match the source", path, value)
+				}
+			}
+		})
+	}
+}
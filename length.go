@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// 길이 제한 규칙 (설정 파일의 lengthLimits)
+// files와 keys 중 지정한 조건을 모두 만족하는 키에 적용되며, 여러 규칙이 맞으면 가장 짧은 제한을 사용한다.
+type LengthRule struct {
+	Files     []string `yaml:"files"`     // 소스 디렉터리 기준 파일 glob
+	Keys      []string `yaml:"keys"`      // 키 경로 glob (예: "buttons/**", "/nav/*")
+	MaxLength int      `yaml:"maxLength"` // 최대 글자 수
+	MaxRatio  float64  `yaml:"maxRatio"`  // 소스 길이 대비 최대 비율 (예: 1.4)
+}
+
+func (r *LengthRule) matches(file, path string) bool {
+	if len(r.Files) > 0 && !matchAnyGlob(r.Files, file) {
+		return false
+	}
+	if len(r.Keys) > 0 {
+		key := strings.TrimPrefix(path, "/")
+		for _, pattern := range r.Keys {
+			if matchGlob(strings.TrimPrefix(pattern, "/"), key) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// 화면에 보이는 글자 수 (HTML 태그 제외)
+func visibleLength(text string) int {
+	return utf8.RuneCountInString(tagPattern.ReplaceAllString(text, ""))
+}
+
+// 글자 수 제한과 비율 제한 중 더 짧은 쪽 (제한이 없으면 0)
+func lengthLimit(source string, maxLength int, maxRatio float64) int {
+	limit := maxLength
+	if maxRatio > 0 {
+		byRatio := int(math.Ceil(maxRatio * float64(visibleLength(source))))
+		if limit == 0 || byRatio < limit {
+			limit = byRatio
+		}
+	}
+	return limit
+}
+
+// 작업의 키별 맥락에 길이 제한 규칙과 키별 maxRatio를 반영해 최종 글자 수 제한을 계산한다.
+// 원래 맥락은 다른 언어 작업과 공유하므로 복사본을 반환한다.
+func applyLengthLimits(job TranslationJob, rules []LengthRule) contextNotes {
+	notes := make(contextNotes, len(job.Context))
+	for path, note := range job.Context {
+		notes[path] = note
+	}
+
+	walkLeaves(job.Content, "", func(path string, value interface{}) {
+		source, ok := value.(string)
+		if !ok {
+			return
+		}
+		note := notes[path]
+		limit := lengthLimit(source, note.MaxLength, note.MaxRatio)
+		for i := range rules {
+			if !rules[i].matches(job.File, path) {
+				continue
+			}
+			if ruleLimit := lengthLimit(source, rules[i].MaxLength, rules[i].MaxRatio); ruleLimit > 0 && (limit == 0 || ruleLimit < limit) {
+				limit = ruleLimit
+			}
+		}
+		if limit > 0 {
+			note.MaxLength = limit
+			note.MaxRatio = 0
+			notes[path] = note
+		}
+	})
+	return notes
+}
+
+// 제한을 넘은 번역을 다시 요청할 때 모델에 전달할 맥락 (이전 번역과 길이를 알려 더 짧은 표현을 요청)
+func shorterVariantNotes(notes contextNotes, overflows map[string]placeholderIssue) contextNotes {
+	if len(overflows) == 0 {
+		return notes
+	}
+	result := make(contextNotes, len(notes))
+	for path, note := range notes {
+		result[path] = note
+	}
+	for path, issue := range overflows {
+		note := result[path]
+		hint := fmt.Sprintf("the previous translation %q has %d characters, over the limit of %d; use a shorter wording", issue.Value, issue.Length, issue.MaxLength)
+		if note.Description != "" {
+			hint = note.Description + "; " + hint
+		}
+		note.Description = hint
+		result[path] = note
+	}
+	return result
+}
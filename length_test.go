@@ -0,0 +1,124 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyLengthLimits(t *testing.T) {
+	content := `{"buttons":{"save":"Save","cancel":"Cancel","nested":{"ok":"OK"}},"title":"Account settings","rich":"<b>Hello</b> world","count":3}`
+	tests := []struct {
+		name    string
+		file    string
+		context contextNotes
+		rules   []LengthRule
+		want    map[string]int // 경로 → MaxLength (없는 경로는 제한 없음)
+	}{
+		{name: "no limits", file: "app.json", want: map[string]int{}},
+		{
+			name:  "key glob rule",
+			file:  "app.json",
+			rules: []LengthRule{{Keys: []string{"buttons/**"}, MaxLength: 12}},
+			want:  map[string]int{"/buttons/save": 12, "/buttons/cancel": 12, "/buttons/nested/ok": 12},
+		},
+		{
+			name:  "single-segment glob with a leading slash",
+			file:  "app.json",
+			rules: []LengthRule{{Keys: []string{"/buttons/*"}, MaxLength: 12}},
+			want:  map[string]int{"/buttons/save": 12, "/buttons/cancel": 12},
+		},
+		{
+			name:  "file glob",
+			file:  "mobile/app.json",
+			rules: []LengthRule{{Files: []string{"web/**"}, MaxLength: 5}, {Files: []string{"mobile/**"}, Keys: []string{"title"}, MaxLength: 20}},
+			want:  map[string]int{"/title": 20},
+		},
+		{
+			name:  "ratio rule counts visible characters",
+			file:  "app.json",
+			rules: []LengthRule{{Keys: []string{"rich", "title"}, MaxRatio: 1.5}},
+			want:  map[string]int{"/rich": 17, "/title": 24},
+		},
+		{
+			name:  "shortest matching rule wins",
+			file:  "app.json",
+			rules: []LengthRule{{Keys: []string{"**"}, MaxLength: 30}, {Keys: []string{"title"}, MaxLength: 18}, {Keys: []string{"title"}, MaxRatio: 2}},
+			want:  map[string]int{"/buttons/save": 30, "/buttons/cancel": 30, "/buttons/nested/ok": 30, "/title": 18, "/rich": 30},
+		},
+		{
+			name:    "per-key maxLength shorter than the rule",
+			file:    "app.json",
+			context: contextNotes{"/buttons/save": {Description: "toolbar", MaxLength: 8}},
+			rules:   []LengthRule{{Keys: []string{"buttons/*"}, MaxLength: 12}},
+			want:    map[string]int{"/buttons/save": 8, "/buttons/cancel": 12},
+		},
+		{
+			name:    "rule shorter than the per-key maxLength",
+			file:    "app.json",
+			context: contextNotes{"/buttons/save": {MaxLength: 40}},
+			rules:   []LengthRule{{Keys: []string{"buttons/*"}, MaxLength: 12}},
+			want:    map[string]int{"/buttons/save": 12, "/buttons/cancel": 12},
+		},
+		{
+			name:    "per-key maxRatio",
+			file:    "app.json",
+			context: contextNotes{"/buttons/cancel": {MaxRatio: 1.4}, "/title": {MaxLength: 30, MaxRatio: 1.2}},
+			want:    map[string]int{"/buttons/cancel": 9, "/title": 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := TranslationJob{File: tt.file, Content: mustParse(t, content), Context: tt.context}
+			notes := applyLengthLimits(job, tt.rules)
+
+			got := make(map[string]int)
+			for path, note := range notes {
+				if note.MaxRatio != 0 {
+					t.Errorf("%s: MaxRatio %v was not converted", path, note.MaxRatio)
+				}
+				if note.MaxLength > 0 {
+					got[path] = note.MaxLength
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("limits = %v, want %v", got, tt.want)
+			}
+			for path, note := range tt.context {
+				if notes[path].Description != note.Description {
+					t.Errorf("%s: description %q, want %q", path, notes[path].Description, note.Description)
+				}
+				if job.Context[path] != note {
+					t.Errorf("%s: shared context was modified", path)
+				}
+			}
+		})
+	}
+}
+
+func TestShorterVariantNotes(t *testing.T) {
+	notes := contextNotes{
+		"/save":  {Description: "toolbar button", MaxLength: 8},
+		"/other": {Description: "unchanged"},
+	}
+	overflows := map[string]placeholderIssue{
+		"/save":  {Path: "/save", Value: "Enregistrer", Length: 11, MaxLength: 8},
+		"/title": {Path: "/title", Value: "Paramètres du compte", Length: 20, MaxLength: 15},
+	}
+	got := shorterVariantNotes(notes, overflows)
+
+	want := contextNotes{
+		"/save":  {Description: `toolbar button; the previous translation "Enregistrer" has 11 characters, over the limit of 8; use a shorter wording`, MaxLength: 8},
+		"/other": {Description: "unchanged"},
+		"/title": {Description: `the previous translation "Paramètres du compte" has 20 characters, over the limit of 15; use a shorter wording`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	if notes["/save"].Description != "toolbar button" || len(notes) != 2 {
+		t.Errorf("original notes were modified: %+v", notes)
+	}
+
+	if same := shorterVariantNotes(notes, nil); !reflect.DeepEqual(same, notes) {
+		t.Errorf("without overflows = %+v, want the notes unchanged", same)
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
-	"path/filepath"
+	"os/signal"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
-	"time"
+	"syscall"
 
 	"log"
 
@@ -18,16 +22,28 @@ import (
 	openai "github.com/sashabaranov/go-openai"
 )
 
+// 기본값 (go-multilingual.yaml 또는 CLI 플래그로 변경 가능)
 const (
-	MAX_CONCURRENT_JOBS = 30 // 최대 동시 실행 고루틴 수
-	MAX_RETRIES         = 1  // 최대 재시도 횟수
-	RETRY_DELAY         = 1  // 재시도 대기 시간(초)
+	MAX_CONCURRENT_JOBS = 30  // 최대 동시 실행 고루틴 수
+	MAX_RETRIES         = 3   // 최대 재시도 횟수 (첫 요청 제외)
+	RETRY_DELAY         = 1   // 첫 재시도 대기 시간(초), 이후 지수적으로 증가
+	DEFAULT_TEMPERATURE = 0.3 // 채팅 모델 temperature
 )
 
+// 기본 브랜드 보이스 지침
+const DEFAULT_BRAND_VOICE = `- Professional yet approachable tone
+- Clear and concise language
+- Maintain technical accuracy for B2B SaaS context
+- Keep marketing messages persuasive and solution-focused
+- Preserve formal business language while being engaging`
+
 type TranslationJob struct {
 	SourceLang string
 	TargetLang string
+	File       string // 소스 로케일 디렉터리 기준 상대 경로
 	Content    interface{}
+	Context    contextNotes    // 키별 맥락 (모델에만 전달)
+	Document   *localeDocument // 소스 문서 (대상 파일 형식과 복수형 객체 위치)
 }
 
 // 로깅 설정
@@ -90,378 +106,528 @@ var languageMap = map[string]string{
 	"lv":  "Latvian",    // 라트비아어
 }
 
-// var languageMap = map[string]string{
-// 	"af":  "Afrikaans",                   // 아프리칸스어
-// 	"agq": "Aghem",                       // 아겜어
-// 	"ak":  "Akan",                        // 아칸어
-// 	"am":  "Amharic",                     // 암하라어
-// 	"ar":  "Arabic",                      // 아랍어
-// 	"as":  "Assamese",                    // 아삼어
-// 	"asa": "Asu",                         // 아수어
-// 	"ast": "Asturian",                    // 아스투리아스어
-// 	"az":  "Azerbaijani",                 // 아제르바이잔어
-// 	"bas": "Basaa",                       // 바사어
-// 	"be":  "Belarusian",                  // 벨라루스어
-// 	"bem": "Bemba",                       // 벰바어
-// 	"bez": "Bena",                        // 베나어
-// 	"bg":  "Bulgarian",                   // 불가리아어
-// 	"bm":  "Bambara",                     // 밤바라어
-// 	"bn":  "Bengali",                     // 벵골어
-// 	"bo":  "Tibetan",                     // 티베트어
-// 	"br":  "Breton",                      // 브르타뉴어
-// 	"brx": "Bodo",                        // 보도어
-// 	"bs":  "Bosnian",                     // 보스니아어
-// 	"ca":  "Catalan",                     // 카탈로니아어
-// 	"ccp": "Chakma",                      // 차크마어
-// 	"ce":  "Chechen",                     // 체첸어
-// 	"cgg": "Chiga",                       // 치가어
-// 	"chr": "Cherokee",                    // 체로키어
-// 	"ckb": "Central Kurdish",             // 중앙 쿠르드어
-// 	"cs":  "Czech",                       // 체코어
-// 	"cy":  "Welsh",                       // 웨일스어
-// 	"da":  "Danish",                      // 덴마크어
-// 	"dav": "Taita",                       // 타이타어
-// 	"de":  "German",                      // 독일어
-// 	"dje": "Zarma",                       // 자르마어
-// 	"dsb": "Lower Sorbian",               // 저지 소르브어
-// 	"dua": "Duala",                       // 두알라어
-// 	"dyo": "Jola-Fonyi",                  // 졸라-포니어
-// 	"dz":  "Dzongkha",                    // 종카어
-// 	"ebu": "Embu",                        // 엠부어
-// 	"ee":  "Ewe",                         // 에웨어
-// 	"el":  "Greek",                       // 그리스어
-// 	"en":  "English",                     // 영어
-// 	"eo":  "Esperanto",                   // 에스페란토어
-// 	"es":  "Spanish",                     // 스페인어
-// 	"et":  "Estonian",                    // 에스토니아어
-// 	"eu":  "Basque",                      // 바스크어
-// 	"ewo": "Ewondo",                      // 에원도어
-// 	"fa":  "Persian",                     // 페르시아어
-// 	"ff":  "Fulah",                       // 풀라어
-// 	"fi":  "Finnish",                     // 핀란드어
-// 	"fil": "Filipino",                    // 필리핀어
-// 	"fo":  "Faroese",                     // 페로어
-// 	"fr":  "French",                      // 프랑스어
-// 	"fur": "Friulian",                    // 프리울리어
-// 	"fy":  "Western Frisian",             // 서프리지아어
-// 	"ga":  "Irish",                       // 아일랜드어
-// 	"gd":  "Scottish Gaelic",             // 스코틀랜드 게일어
-// 	"gl":  "Galician",                    // 갈리시아어
-// 	"gsw": "Swiss German",                // 스위스 독일어
-// 	"gu":  "Gujarati",                    // 구자라트어
-// 	"guz": "Gusii",                       // 구시어
-// 	"gv":  "Manx",                        // 맨섬어
-// 	"ha":  "Hausa",                       // 하우사어
-// 	"haw": "Hawaiian",                    // 하와이어
-// 	"he":  "Hebrew",                      // 히브리어
-// 	"hi":  "Hindi",                       // 힌디어
-// 	"hr":  "Croatian",                    // 크로아티아어
-// 	"hsb": "Upper Sorbian",               // 고지 소르브어
-// 	"hu":  "Hungarian",                   // 헝가리어
-// 	"hy":  "Armenian",                    // 아르메니아어
-// 	"id":  "Indonesian",                  // 인도네시아어
-// 	"ig":  "Igbo",                        // 이그보어
-// 	"ii":  "Sichuan Yi",                  // 쓰촨 이어
-// 	"is":  "Icelandic",                   // 아이슬란드어
-// 	"it":  "Italian",                     // 이탈리아어
-// 	"ja":  "Japanese",                    // 일본어
-// 	"jgo": "Ngomba",                      // 응곰바어
-// 	"jmc": "Machame",                     // 마차메어
-// 	"ka":  "Georgian",                    // 조지아어
-// 	"kab": "Kabyle",                      // 카빌어
-// 	"kam": "Kamba",                       // 캄바어
-// 	"kde": "Makonde",                     // 마콘데어
-// 	"kea": "Kabuverdianu",                // 카보베르데어
-// 	"khq": "Koyra Chiini",                // 코이라 치니어
-// 	"ki":  "Kikuyu",                      // 키쿠유어
-// 	"kk":  "Kazakh",                      // 카자흐어
-// 	"kkj": "Kako",                        // 카코어
-// 	"kl":  "Kalaallisut",                 // 그린란드어
-// 	"kln": "Kalenjin",                    // 칼렌진어
-// 	"km":  "Khmer",                       // 크메르어
-// 	"kn":  "Kannada",                     // 칸나다어
-// 	"ko":  "Korean",                      // 한국어
-// 	"kok": "Konkani",                     // 콘칸어
-// 	"ks":  "Kashmiri",                    // 카슈미르어
-// 	"ksb": "Shambala",                    // 샴발라어
-// 	"ksf": "Bafia",                       // 바피아어
-// 	"ksh": "Colognian",                   // 쾰른어
-// 	"kw":  "Cornish",                     // 콘월어
-// 	"ky":  "Kyrgyz",                      // 키르기스어
-// 	"lag": "Langi",                       // 랑기어
-// 	"lb":  "Luxembourgish",               // 룩셈부르크어
-// 	"lg":  "Ganda",                       // 간다어
-// 	"lkt": "Lakota",                      // 라코타어
-// 	"ln":  "Lingala",                     // 링갈라어
-// 	"lo":  "Lao",                         // 라오어
-// 	"lrc": "Northern Luri",               // 북부 루리어
-// 	"lt":  "Lithuanian",                  // 리투아니아어
-// 	"lu":  "Luba-Katanga",                // 루바-카탕가어
-// 	"luo": "Luo",                         // 루오어
-// 	"luy": "Luyia",                       // 루이아어
-// 	"lv":  "Latvian",                     // 라트비아어
-// 	"mas": "Masai",                       // 마사이어
-// 	"mer": "Meru",                        // 메루어
-// 	"mfe": "Morisyen",                    // 모리셔스 크레올어
-// 	"mg":  "Malagasy",                    // 말라가시어
-// 	"mgh": "Makhuwa-Meetto",              // 마쿠아-메토어
-// 	"mgo": "Metaʼ",                       // 메타어
-// 	"mk":  "Macedonian",                  // 마케도니아어
-// 	"ml":  "Malayalam",                   // 말라얄람어
-// 	"mn":  "Mongolian",                   // 몽골어
-// 	"mr":  "Marathi",                     // 마라티어
-// 	"ms":  "Malay",                       // 말레이어
-// 	"mt":  "Maltese",                     // 몰타어
-// 	"mua": "Mundang",                     // 문당어
-// 	"my":  "Burmese",                     // 미얀마어
-// 	"mzn": "Mazanderani",                 // 마잔데라니어
-// 	"naq": "Nama",                        // 나마어
-// 	"nb":  "Norwegian Bokmål",            // 노르웨이 부크몰
-// 	"nd":  "North Ndebele",               // 북부 은데벨레어
-// 	"nds": "Low German",                  // 저지 독일어
-// 	"ne":  "Nepali",                      // 네팔어
-// 	"nl":  "Dutch",                       // 네덜란드어
-// 	"nmg": "Kwasio",                      // 콰시오어
-// 	"nn":  "Norwegian Nynorsk",           // 노르웨이 뉘노르스크
-// 	"nnh": "Ngiemboon",                   // 느기엠분어
-// 	"nus": "Nuer",                        // 누에르어
-// 	"nyn": "Nyankole",                    // 냔콜레어
-// 	"om":  "Oromo",                       // 오로모어
-// 	"or":  "Odia",                        // 오디아어
-// 	"os":  "Ossetic",                     // 오세트어
-// 	"pa":  "Punjabi",                     // 펀자브어
-// 	"pl":  "Polish",                      // 폴란드어
-// 	"ps":  "Pashto",                      // 파슈토어
-// 	"pt":  "Portuguese",                  // 포르투갈어
-// 	"qu":  "Quechua",                     // 케추아어
-// 	"rm":  "Romansh",                     // 로만시어
-// 	"rn":  "Rundi",                       // 룬디어
-// 	"ro":  "Romanian",                    // 루마니아어
-// 	"rof": "Rombo",                       // 롬보어
-// 	"ru":  "Russian",                     // 러시아어
-// 	"rw":  "Kinyarwanda",                 // 키냐르완다어
-// 	"rwk": "Rwa",                         // 르와어
-// 	"sah": "Sakha",                       // 사하어
-// 	"saq": "Samburu",                     // 삼부루어
-// 	"sbp": "Sangu",                       // 상구어
-// 	"se":  "Northern Sami",               // 북부 사미어
-// 	"seh": "Sena",                        // 세나어
-// 	"ses": "Koyraboro Senni",             // 코이라보로 세니어
-// 	"sg":  "Sango",                       // 상고어
-// 	"shi": "Tachelhit",                   // 타셸히트어
-// 	"si":  "Sinhala",                     // 싱할라어
-// 	"sk":  "Slovak",                      // 슬로바키아어
-// 	"sl":  "Slovenian",                   // 슬로베니아어
-// 	"smn": "Inari Sami",                  // 이나리 사미어
-// 	"sn":  "Shona",                       // 쇼나어
-// 	"so":  "Somali",                      // 소말리어
-// 	"sq":  "Albanian",                    // 알바니아어
-// 	"sr":  "Serbian",                     // 세르비아어
-// 	"sv":  "Swedish",                     // 스웨덴어
-// 	"sw":  "Swahili",                     // 스와힐리어
-// 	"ta":  "Tamil",                       // 타밀어
-// 	"te":  "Telugu",                      // 텔루구어
-// 	"teo": "Teso",                        // 테소어
-// 	"tg":  "Tajik",                       // 타지크어
-// 	"th":  "Thai",                        // 태국어
-// 	"ti":  "Tigrinya",                    // 티그리냐어
-// 	"to":  "Tongan",                      // 통가어
-// 	"tr":  "Turkish",                     // 터키어
-// 	"tt":  "Tatar",                       // 타타르어
-// 	"twq": "Tasawaq",                     // 타사와크어
-// 	"tzm": "Central Atlas Tamazight",     // 중앙 아틀라스 타마지트어
-// 	"ug":  "Uyghur",                      // 위구르어
-// 	"uk":  "Ukrainian",                   // 우크라이나어
-// 	"ur":  "Urdu",                        // 우르두어
-// 	"uz":  "Uzbek",                       // 우즈베크어
-// 	"vai": "Vai",                         // 바이어
-// 	"vi":  "Vietnamese",                  // 베트남어
-// 	"vun": "Vunjo",                       // 분조어
-// 	"wae": "Walser",                      // 발저어
-// 	"wo":  "Wolof",                       // 월로프어
-// 	"xog": "Soga",                        // 소가어
-// 	"yav": "Yangben",                     // 양벤어
-// 	"yi":  "Yiddish",                     // 이디시어
-// 	"yo":  "Yoruba",                      // 요루바어
-// 	"yue": "Cantonese",                   // 광둥어
-// 	"zgh": "Standard Moroccan Tamazight", // 표준 모로코 타마지트어
-// 	"zh":  "Chinese",                     // 중국어
-// 	"zu":  "Zulu",                        // 줄루어
-// }
-
 func main() {
-	// .env 파일 로드
-	if err := godotenv.Load(); err != nil {
-		fmt.Printf("Error loading .env file: %v\n", err)
-		return
+	if len(os.Args) < 2 {
+		printUsage(os.Stderr)
+		os.Exit(2)
 	}
 
-	// OpenAI API 키 확인
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		fmt.Println("OPENAI_API_KEY is not set in .env file")
-		return
+	switch os.Args[1] {
+	case "translate":
+		opts, err := parseTranslateFlags(os.Args[2:])
+		if err == flag.ErrHelp {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			printUsage(os.Stderr)
+			os.Exit(2)
+		}
+		if err := runTranslate(opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "tm":
+		err := runMemoryCommand(os.Args[2:])
+		if err == flag.ErrHelp {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "xliff":
+		err := runXLIFFCommand(os.Args[2:])
+		if err == flag.ErrHelp {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "check":
+		err := runCheckCommand(os.Args[2:])
+		if err == flag.ErrHelp {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "languages":
+		printLanguages(os.Stdout)
+	case "help", "-h", "--help":
+		printUsage(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+}
+
+func runTranslate(opts *translateOptions) error {
+	// .env 파일 로드 (CI 환경에서는 파일 없이 환경 변수만 사용할 수 있음)
+	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error loading .env file: %v", err)
 	}
 
-	// 1. 소스 JSON 파일 읽기
-	sourceFile := "locales/en/common.json"
-	data, err := os.ReadFile(sourceFile)
+	// 1. 소스 로케일 파일 탐색 및 파싱
+	sourceFiles, err := discoverSourceFiles(opts.Source, opts.SourceLang, opts.Include, opts.ExcludeFiles)
 	if err != nil {
-		fmt.Printf("Error reading source file: %v\n", err)
-		return
+		return err
+	}
+
+	// 2. 작업 목록 생성
+	// --resume이면 이전 실행에서 완료되지 않은 작업만, 아니면 대상 언어 × 파일 전체
+	var jobs []TranslationJob
+	var state *runState
+	if opts.Resume {
+		state, err = loadRunState(opts.StateFile)
+		if err != nil {
+			return err
+		}
+		jobs = state.unfinishedJobs(sourceFiles)
+		if len(jobs) == 0 {
+			fmt.Println("Nothing to resume: every job of the previous run is done")
+			return nil
+		}
+		fmt.Printf("Resuming %d unfinished jobs from %s\n", len(jobs), opts.StateFile)
+	} else {
+		targetLanguages := resolveTargetLanguages(opts)
+		if len(targetLanguages) == 0 {
+			return fmt.Errorf("no target languages left after applying --exclude")
+		}
+		for _, lang := range targetLanguages {
+			for _, file := range sourceFiles {
+				jobs = append(jobs, TranslationJob{
+					SourceLang: opts.SourceLang,
+					TargetLang: lang,
+					File:       file.RelPath,
+					Content:    file.Content,
+					Context:    file.Context,
+					Document:   file.Document,
+				})
+			}
+		}
+		state = newRunState(opts.StateFile, opts, jobs)
 	}
 
-	// 2. JSON 파싱
-	var content map[string]interface{}
-	if err := json.Unmarshal(data, &content); err != nil {
-		fmt.Printf("Error parsing JSON: %v\n", err)
-		return
+	// 3. 작업에 포함된 대상 언어
+	var targetLanguages []string
+	seenLanguages := make(map[string]bool)
+	for _, job := range jobs {
+		if !seenLanguages[job.TargetLang] {
+			seenLanguages[job.TargetLang] = true
+			targetLanguages = append(targetLanguages, job.TargetLang)
+		}
 	}
-	//    // 3. 대상 언어 리스트 정의
-	// var targetLanguages []string
-	// for langCode := range languageMap {
-	// 	if langCode != "en" { // 소스 언어(한국어)는 제외
-	// 		targetLanguages = append(targetLanguages, langCode)
-	// 	}
-	// }
-	// 실패한 언어 목록
-	targetLanguages := []string{
-		"id", "vi", "ur", "km", "my", "fil", "el", "ms",
-		"pl", "si",
+	for _, lang := range targetLanguages {
+		if _, ok := languageMap[lang]; !ok {
+			log.Printf("Warning: language code %q is not in languageMap, using the code as its name", lang)
+		}
 	}
 
-	// targetLanguages := []string{
-	// 	"rm", "kw", "ml",
-	// }
+	// 4. 번역 백엔드 초기화 (실행 기본값 + 언어별 지정)
+	translators, err := newTranslatorSet(opts)
+	if err != nil {
+		return err
+	}
+
+	// 번역 메모리: 이전 실행에서 검증을 통과한 번역을 재사용 (--dry-run은 없는 메모리 파일을 만들지 않는다)
+	useMemory := !opts.NoMemory
+	if _, err := os.Stat(opts.MemoryFile); opts.DryRun && err != nil {
+		useMemory = false
+	}
+	if useMemory {
+		memory, err := openTranslationMemory(opts.MemoryFile)
+		if err != nil {
+			return err
+		}
+		defer memory.Close()
+		opts.memory = memory
+	}
+
+	// XLIFF로 가져와 사람이 승인한 키
+	if opts.approvals, err = loadApprovals(opts.ApprovalsFile); err != nil {
+		return err
+	}
 
-	// 4. OpenAI 클라이언트 초기화
-	client := openai.NewClient(apiKey)
+	// --dry-run: 요청과 파일 쓰기 없이 예상 토큰 수와 비용만 출력
+	if opts.DryRun {
+		return estimateRun(os.Stdout, jobs, translators, opts)
+	}
+	if err := state.Save(); err != nil {
+		return fmt.Errorf("error writing run state: %v", err)
+	}
+
+	// 작업별 결과와 사용량 (언어별, 파일별로 합산해 출력하고 --report 파일로 저장)
+	report := newRunReport(opts.ReportFile, targetLanguages)
 
 	// 진행 상황 추적을 위한 변수들
-	totalLanguages := len(targetLanguages)
+	totalJobs := len(jobs)
 	completedCount := 0
 	successCount := 0
 	var progressMutex sync.Mutex
 
 	// 번역 결과와 에러를 저장할 채널 생성
 	type translationResult struct {
-		lang    string
-		content interface{}
+		job     TranslationJob
+		issues  []placeholderIssue
 		err     error
+		status  string
+		backend string
+		usage   usageTotals
 	}
-	resultChan := make(chan translationResult, len(targetLanguages))
+	resultChan := make(chan translationResult, len(jobs))
 
 	// 세마포어 생성
-	sem := make(chan struct{}, MAX_CONCURRENT_JOBS)
+	sem := make(chan struct{}, opts.Jobs)
 	var wg sync.WaitGroup
 
 	// 진행 상황 출력 함수
-	printProgress := func(lang string, success bool, err error) {
+	printProgress := func(job TranslationJob, success bool, err error) {
 		completedCount++
 		if success {
 			successCount++
 		}
 
-		percentage := float64(completedCount) / float64(totalLanguages) * 100
+		percentage := float64(completedCount) / float64(totalJobs) * 100
 		fmt.Printf("\n=== 번역 진행 상황 ===\n")
-		fmt.Printf("총 언어: %d개\n", totalLanguages)
-		fmt.Printf("완료된 언어: %d개 (%.1f%%)\n", completedCount, percentage)
+		fmt.Printf("총 작업: %d개 (언어 %d개, 파일 %d개)\n", totalJobs, len(targetLanguages), len(sourceFiles))
+		fmt.Printf("완료된 작업: %d개 (%.1f%%)\n", completedCount, percentage)
 		fmt.Printf("성공: %d개, 실패: %d개\n", successCount, completedCount-successCount)
-		fmt.Printf("현재 처리 중인 언어: %s (%s)\n", languageMap[lang], lang)
+		fmt.Printf("현재 처리 중인 작업: %s (%s) - %s\n", languageMap[job.TargetLang], job.TargetLang, job.File)
 		if err != nil {
 			fmt.Printf("오류 내용: %v\n", err)
 		}
 		fmt.Printf("=====================\n\n")
 	}
 
-	// 5. 각 언어별로 고루틴을 사용하여 동시 번역 수행
-	for _, lang := range targetLanguages {
-		sem <- struct{}{} // 세마포어 획득
+	// Ctrl-C(SIGINT)/SIGTERM을 받으면 진행 중인 요청을 취소하고 새 작업을 시작하지 않는다
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// 지출 한도: 실행 한도를 넘으면 ctx가, 언어 한도를 넘으면 그 언어의 컨텍스트가 취소된다
+	budget, ctx := newSpendBudget(ctx, opts.Budget, opts.LanguageBudget)
+	if budget != nil && (opts.Budget.MaxCost > 0 || opts.LanguageBudget.MaxCost > 0) {
+		for _, lang := range targetLanguages {
+			translator := translators.forLanguage(lang)
+			if _, priced := priceFor(translator, opts.Pricing); !priced {
+				log.Printf("Warning: no price for %s in pricing, the cost limit does not count its requests", translator.Name())
+			}
+		}
+	}
+
+	// 5. 각 작업별로 고루틴을 사용하여 동시 번역 수행
+	var notStarted []TranslationJob            // 중단되었거나 지출 한도 때문에 시작하지 않은 작업
+	notStartedReason := make(map[string]error) // 언어 → 지출 한도 때문에 시작하지 않은 이유
+	var budgetStop error                       // 새 작업 시작을 모두 멈춘 실행 지출 한도
+	for i, job := range jobs {
+		select {
+		case sem <- struct{}{}: // 세마포어 획득
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			notStarted = append(notStarted, jobs[i:]...)
+			break
+		}
+
+		job = prepareJob(job, opts)
+		translator := translators.forLanguage(job.TargetLang).WithPrompt(opts.prompts.forJob(job)).WithContext(job.Context)
+
+		// 예상 지출이 한도를 넘으면 시작하지 않는다 (실행 한도면 이후 작업 전체, 언어 한도면 그 언어만)
+		var spending *budgetJob
+		if budget != nil {
+			projected, err := estimateJob(translator, job, opts)
+			if err == nil {
+				price, _ := priceFor(translator, opts.Pricing)
+				spending, err = budget.start(job.TargetLang, projected, price)
+			}
+			var exceeded *budgetExceededError
+			if errors.As(err, &exceeded) {
+				<-sem
+				notStarted = append(notStarted, job)
+				if exceeded.Lang == "" {
+					budgetStop = exceeded
+					log.Printf("%v: not starting the remaining %d jobs", exceeded, len(jobs)-i)
+					notStarted = append(notStarted, jobs[i+1:]...)
+					break
+				}
+				log.Printf("%v: skipping %s", exceeded, job.File)
+				notStartedReason[job.TargetLang] = exceeded
+				continue
+			}
+			if err != nil {
+				log.Printf("Warning: could not estimate %s (%s) for the budget: %v", job.TargetLang, job.File, err)
+			}
+		}
+
 		wg.Add(1)
-		go func(lang string) {
+		go func(job TranslationJob, translator Translator, spending *budgetJob) {
 			defer wg.Done()
 			defer func() { <-sem }() // 세마포어 반환
+			defer spending.finish()
 
-			var translatedContent interface{}
-			var err error
+			stats := &requestStats{budget: spending}
+			jobCtx := budget.languageContext(ctx, job.TargetLang)
+			translatedContent, issues, err := translateJob(jobCtx, translator, job, opts, stats)
 
-			// 재시도 로직
-			for retry := 0; retry < MAX_RETRIES; retry++ {
-				translatedContent, err = translateContent(client, content, "en", lang)
-				if err == nil {
-					break
-				}
+			// 6. 완료된 작업은 바로 파일로 저장 (실행이 중단되어도 완료된 번역은 남는다)
+			var outputHash string
+			if err == nil {
+				outputHash, err = saveJobResult(job, translatedContent, opts)
+			}
 
-				log.Printf("Translation retry %d for language %s: %v", retry+1, lang, err)
-				if retry < MAX_RETRIES-1 {
-					time.Sleep(time.Second * RETRY_DELAY)
+			// 작업 상태 기록 (중단으로 취소된 작업은 --resume에서 다시 실행되도록 pending으로 남긴다)
+			status := jobFailed
+			switch {
+			case err == nil:
+				status = jobDone
+				state.update(job, jobDone, nil, stats.Requests(), outputHash, translator.PromptHash())
+			case jobCtx.Err() != nil:
+				// 지출 한도로 취소되었으면 그 이유를 기록한다
+				if cause := context.Cause(jobCtx); cause != jobCtx.Err() {
+					err = cause
 				}
+				status = jobPending
+				state.update(job, jobPending, err, stats.Requests(), "", "")
+			default:
+				state.update(job, jobFailed, err, stats.Requests(), "", "")
 			}
 
 			// 진행 상황 출력을 위한 뮤텍스 잠금
 			progressMutex.Lock()
-			printProgress(lang, err == nil, err)
+			printProgress(job, err == nil, err)
 			progressMutex.Unlock()
 
 			// 결과 전송
-			resultChan <- translationResult{lang: lang, content: translatedContent, err: err}
-		}(lang)
+			resultChan <- translationResult{
+				job:     job,
+				issues:  issues,
+				err:     err,
+				status:  status,
+				backend: translator.Name(),
+				usage:   stats.usage(translator, opts.Pricing),
+			}
+		}(job, translator, spending)
 	}
 
 	// 모든 고루틴이 완료될 때까지 대기
 	wg.Wait()
 	close(resultChan)
 
-	// 번역 실패한 언어를 저장할 슬라이스
-	var failedLanguages []string
+	// 번역 실패한 작업을 저장할 슬라이스
+	type failedJob struct {
+		job TranslationJob
+		err error
+	}
+	var failedJobs []failedJob
+	var cancelledJobs []failedJob // 중단되었거나 지출 한도로 취소된 작업 (--resume으로 이어서 번역)
+
+	// 재요청 후에도 플레이스홀더/태그가 맞지 않는 값
+	var mismatchedResults []translationResult
 
 	// 모든 번역 결과 수집
 	for result := range resultChan {
+		report.addJob(result.job, result.backend, result.status, result.err, result.usage)
+		if result.status == jobPending {
+			cancelledJobs = append(cancelledJobs, failedJob{job: result.job, err: result.err})
+			continue
+		}
 		if result.err != nil {
-			fmt.Printf("Translation failed for language %s (%s): %v\n", languageMap[result.lang], result.lang, result.err)
-			failedLanguages = append(failedLanguages, result.lang)
+			failedJobs = append(failedJobs, failedJob{job: result.job, err: result.err})
 			continue
 		}
+		if len(result.issues) > 0 {
+			mismatchedResults = append(mismatchedResults, result)
+		}
+	}
 
-		// 6. 번역된 내용을 파일로 저장
-		outputDir := filepath.Join("locales", result.lang)
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			fmt.Printf("Error creating directory for %s: %v\n", result.lang, err)
-			failedLanguages = append(failedLanguages, result.lang)
-			continue
+	// 플레이스홀더/태그 불일치가 남은 문자열 출력
+	if len(mismatchedResults) > 0 {
+		sort.Slice(mismatchedResults, func(i, j int) bool {
+			a, b := mismatchedResults[i].job, mismatchedResults[j].job
+			if a.TargetLang != b.TargetLang {
+				return a.TargetLang < b.TargetLang
+			}
+			return a.File < b.File
+		})
+		printIssues := func(title string, include func(placeholderIssue) bool, describe func(placeholderIssue) string) {
+			printed := false
+			for _, result := range mismatchedResults {
+				for _, issue := range result.issues {
+					if !include(issue) {
+						continue
+					}
+					if !printed {
+						fmt.Println(title)
+						printed = true
+					}
+					fmt.Printf("- %s (%s) %s %s\n", languageMap[result.job.TargetLang], result.job.TargetLang, result.job.File, describe(issue))
+				}
+			}
 		}
+		printIssues("\nPlaceholder, tag, ICU or glossary mismatches remaining after retries:",
+			func(issue placeholderIssue) bool { return len(issue.Problems) > 0 },
+			func(issue placeholderIssue) string {
+				return fmt.Sprintf("%s: %s", issue.Path, strings.Join(issue.Problems, ", "))
+			})
+		// 길이 제한을 넘은 문자열 (더 짧은 표현을 요청한 뒤에도 남은 것)
+		printIssues("\nStrings over their length limit:",
+			placeholderIssue.overflow,
+			func(issue placeholderIssue) string {
+				return fmt.Sprintf("%s: %d/%d characters %q", issue.Path, issue.Length, issue.MaxLength, issue.Value)
+			})
+	}
 
-		outputFile := filepath.Join(outputDir, "common.json")
-		translatedJSON, err := json.MarshalIndent(result.content, "", "  ")
-		if err != nil {
-			fmt.Printf("Error marshaling JSON for %s: %v\n", result.lang, err)
-			continue
+	for _, job := range notStarted {
+		report.addJob(job, translators.forLanguage(job.TargetLang).Name(), jobPending, nil, usageTotals{})
+	}
+	printAttemptSummary(os.Stdout, report)
+
+	// 번역 실패하거나 취소된 작업이 있다면 파일별로 출력
+	printJobs := func(title string, jobs []failedJob) {
+		if len(jobs) == 0 {
+			return
 		}
+		sort.Slice(jobs, func(i, j int) bool {
+			a, b := jobs[i].job, jobs[j].job
+			if a.TargetLang != b.TargetLang {
+				return a.TargetLang < b.TargetLang
+			}
+			return a.File < b.File
+		})
+		fmt.Println(title)
+		for _, failed := range jobs {
+			fmt.Printf("- %s (%s) %s: %v\n", languageMap[failed.job.TargetLang], failed.job.TargetLang, failed.job.File, failed.err)
+		}
+	}
+	printJobs("\nTranslation failed for the following files:", failedJobs)
+	printJobs("\nCancelled because the run was interrupted or over budget:", cancelledJobs)
+
+	// 실행 요약과 기계가 읽을 수 있는 보고서
+	fmt.Printf("\nSummary: %d succeeded, %d failed, %d unfinished; %d requests, %s\n", report.Succeeded, report.Failed, report.Unfinished, report.Total.Requests, report.Total)
+	if err := report.Save(); err != nil {
+		log.Printf("Warning: could not write run report: %v", err)
+	} else {
+		fmt.Printf("Run report written to %s\n", opts.ReportFile)
+	}
 
-		// 파일 존재 여부 확인
-		if _, err := os.Stat(outputFile); err == nil {
-			fmt.Printf("Overwriting existing file: %s\n", outputFile)
+	// 중단되었거나 지출 한도에 걸려 시작하지 못한 작업 출력
+	stopReason := budgetStop
+	if ctx.Err() != nil {
+		stopReason = context.Cause(ctx)
+	}
+	if len(notStarted) > 0 {
+		fmt.Println("\nNot started because the run was interrupted or over budget:")
+		for _, job := range notStarted {
+			reason := notStartedReason[job.TargetLang]
+			if reason == nil {
+				reason = stopReason
+			}
+			fmt.Printf("- %s (%s) %s: %v\n", languageMap[job.TargetLang], job.TargetLang, job.File, reason)
 		}
+	}
+	if stopReason != nil {
+		return fmt.Errorf("run stopped (%v): %d of %d jobs completed (continue with --resume)", stopReason, report.Succeeded, totalJobs)
+	}
+	if unfinished := len(notStarted) + len(cancelledJobs); unfinished > 0 {
+		return fmt.Errorf("%d of %d jobs unfinished because their language went over budget, %d failed (continue with --resume)", unfinished, totalJobs, len(failedJobs))
+	}
+	if len(failedJobs) > 0 {
+		return fmt.Errorf("%d of %d translation jobs failed (rerun them with --resume)", len(failedJobs), totalJobs)
+	}
+	return nil
+}
 
-		if err := os.WriteFile(outputFile, translatedJSON, 0644); err != nil {
-			fmt.Printf("Error writing file for %s: %v\n", result.lang, err)
-			failedLanguages = append(failedLanguages, result.lang)
-			continue
+// 번역 결과를 대상 파일로 저장하고 다음 증분 실행을 위해 번역에 사용한 소스를 기록한다.
+// 저장한 파일 내용의 해시를 반환한다.
+func saveJobResult(job TranslationJob, content interface{}, opts *translateOptions) (string, error) {
+	outputFile := targetFilePath(opts.OutDir, job.TargetLang, job.File)
+	data, err := writeLocaleFile(outputFile, job.Document, content, job.TargetLang)
+	if err != nil {
+		return "", err
+	}
+
+	if err := saveSnapshot(snapshotPath(opts.SnapshotDir, job.TargetLang, job.File), job.Content); err != nil {
+		log.Printf("Warning: could not save snapshot for %s (%s): %v", job.TargetLang, job.File, err)
+	}
+
+	fmt.Printf("Successfully translated and saved to %s\n", outputFile)
+	return hashBytes(data), nil
+}
+
+// 대상 언어에 맞게 작업을 준비한다: i18next 복수형 키 확장, 길이 제한 반영.
+// 소스 콘텐츠와 맥락은 다른 언어 작업과 공유하므로 바꾸지 않고 새 값을 만든다.
+func prepareJob(job TranslationJob, opts *translateOptions) TranslationJob {
+	notes := make(contextNotes)
+	notes.merge(job.Context)
+	if opts.PluralKeys {
+		var pluralNotes contextNotes
+		job.Content, pluralNotes = expandPluralKeys(job.Content, job.SourceLang, job.TargetLang, job.Document.Plurals)
+		notes.merge(pluralNotes)
+	}
+	job.Context = notes
+	job.Context = applyLengthLimits(job, opts.LengthRules)
+	return job
+}
+
+// 작업 하나를 번역한다. 증분 모드에서는 새로 추가되거나 변경된 키만 번역해 기존 번역과 합친다.
+func translateJob(ctx context.Context, translator Translator, job TranslationJob, opts *translateOptions, stats *requestStats) (interface{}, []placeholderIssue, error) {
+	content := job.Content
+	plan, err := planTranslation(job, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if plan != nil {
+		if len(plan.Pending) == 0 {
+			return plan.merge(job.Content, nil), nil, nil
 		}
+		content = plan.pendingContent(job.Content)
+	}
+
+	translatedContent, issues, err := translateChunked(ctx, translator, content, job.SourceLang, job.TargetLang, job.Context, opts, stats)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		fmt.Printf("Successfully translated and saved to %s\n", outputFile)
+	if plan != nil {
+		return plan.merge(job.Content, translatedContent), issues, nil
+	}
+	return translatedContent, issues, nil
+}
+
+// 작업에서 번역할 키를 정한다. 증분 모드가 아니고 승인된 키도 없으면 nil (전체 번역)
+func planTranslation(job TranslationJob, opts *translateOptions) (*translationPlan, error) {
+	// 사람이 승인한 키는 번역하지 않고 대상 파일의 값을 유지한다
+	approved, err := approvedValues(job, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// 번역 실패한 언어가 있다면 출력
-	if len(failedLanguages) > 0 {
-		fmt.Println("\nTranslation failed for the following languages:")
-		for _, lang := range failedLanguages {
-			fmt.Printf("- %s (%s)\n", languageMap[lang], lang)
+	var plan *translationPlan
+	if opts.Incremental {
+		plan, err = planJob(job, opts.OutDir, opts.SnapshotDir)
+		if err != nil {
+			return nil, err
 		}
+		log.Printf("%s (%s): 추가 %d개, 변경 %d개, 삭제 %d개", job.TargetLang, job.File, plan.Added, plan.Modified, plan.Removed)
+	} else if len(approved) > 0 {
+		plan = planAll(job.Content)
 	}
+	if plan != nil && len(approved) > 0 {
+		plan.keep(approved)
+		log.Printf("%s (%s): 승인된 키 %d개 유지", job.TargetLang, job.File, len(approved))
+	}
+	return plan, nil
 }
 
-func translateContent(client *openai.Client, content interface{}, sourceLang, targetLang string) (interface{}, error) {
+func (t *chatTranslator) translateContent(ctx context.Context, content interface{}, sourceLang, targetLang string) (interface{}, error) {
 	try := func() (interface{}, error) {
 		log.Printf("데이터 처리 시작")
 
@@ -469,22 +635,21 @@ func translateContent(client *openai.Client, content interface{}, sourceLang, ta
 			return nil, fmt.Errorf("입력 데이터가 비어있습니다")
 		}
 
-		// 전체 콘텐츠를 JSON 문자열로 변환
-		jsonContent, err := json.Marshal(content)
+		prompt, err := t.buildPrompt(content, sourceLang, targetLang)
 		if err != nil {
-			return nil, fmt.Errorf("JSON 변환 중 오류: %v", err)
+			return nil, err
 		}
 
 		// 전체 텍스트 번역 수행
-		translatedJSON, err := translateText(client, string(jsonContent), sourceLang, targetLang)
+		translatedJSON, err := t.translateText(ctx, prompt)
 		if err != nil {
-			return nil, fmt.Errorf("번역 중 오류: %v", err)
+			return nil, fmt.Errorf("번역 중 오류: %w", err)
 		}
 
-		// 번역된 JSON 파싱
-		var result interface{}
-		if err := json.Unmarshal([]byte(translatedJSON), &result); err != nil {
-			return nil, fmt.Errorf("번역된 JSON 파싱 중 오류: %v", err)
+		// 번역된 JSON 파싱 (키 순서 유지)
+		result, err := parseOrderedJSON([]byte(translatedJSON))
+		if err != nil {
+			return nil, &invalidOutputError{msg: fmt.Sprintf("번역된 JSON 파싱 중 오류: %v", err)}
 		}
 
 		log.Printf("데이터 처리 완료")
@@ -500,7 +665,24 @@ func translateContent(client *openai.Client, content interface{}, sourceLang, ta
 	return result, nil
 }
 
-func translateText(client *openai.Client, text, sourceLang, targetLang string) (string, error) {
+// 요청 콘텐츠로 모델에 보낼 프롬프트를 만든다. (--dry-run은 요청 없이 이 프롬프트로 토큰 수를 센다)
+func (t *chatTranslator) buildPrompt(content interface{}, sourceLang, targetLang string) (string, error) {
+	// 전체 콘텐츠를 JSON 문자열로 변환
+	jsonContent, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("JSON 변환 중 오류: %v", err)
+	}
+
+	// 이 요청에 등장하는 용어집 항목과 키 맥락만 프롬프트에 넣는다
+	var texts []string
+	icu := false
+	walkLeaves(content, "", func(_ string, value interface{}) {
+		if text, ok := value.(string); ok {
+			texts = append(texts, text)
+			icu = icu || isICUMessage(text)
+		}
+	})
+
 	sourceLanguage := languageMap[sourceLang]
 	targetLanguage := languageMap[targetLang]
 
@@ -511,49 +693,58 @@ func translateText(client *openai.Client, text, sourceLang, targetLang string) (
 		targetLanguage = targetLang
 	}
 
-	prompt := fmt.Sprintf(`You are a professional translator specializing in B2B SaaS localization.
+	return t.prompt.render(promptData{
+		SourceLanguage:    sourceLanguage,
+		SourceLang:        sourceLang,
+		TargetLanguage:    targetLanguage,
+		TargetLang:        targetLang,
+		BrandVoice:        strings.TrimSpace(t.brandVoice),
+		Glossary:          promptGlossary(t.glossary.termsIn(texts, targetLang), targetLang),
+		Context:           t.notes.forContent(content),
+		Payload:           string(jsonContent),
+		ICU:               icu,
+		PluralCategories:  cldrPluralCategories(targetLang, false),
+		OrdinalCategories: cldrPluralCategories(targetLang, true),
+	})
+}
 
-Task: Translate the following JSON from %s (%s) to %s (%s) while maintaining the following requirements:
+func (t *chatTranslator) translateText(ctx context.Context, prompt string) (string, error) {
+	// 응답 헤더(Retry-After 등)를 받아 오기 위한 컨텍스트
+	var headers http.Header
+	ctx = context.WithValue(ctx, responseHeadersKey{}, &headers)
 
-Brand Voice Guidelines:
-- Professional yet approachable tone
-- Clear and concise language
-- Maintain technical accuracy for B2B SaaS context
-- Keep marketing messages persuasive and solution-focused
-- Preserve formal business language while being engaging
-
-Translation Requirements:
-1. Maintain exact JSON structure and keys (do not translate keys)
-2. Only translate the values
-3. Preserve any placeholders like {language}, {number}, {step}
-4. Keep HTML tags and formatting intact
-5. Maintain line breaks indicated by \n
-6. Keep technical terms consistent throughout
-7. Adapt cultural nuances appropriately for the target language
-8. Preserve any numerical values and units
-
-IMPORTANT: Return ONLY the raw JSON without any markdown formatting or code blocks.
-Do not wrap the response in `+"```json```"+` tags.
-
-Source JSON to translate:
-%s`, sourceLanguage, sourceLang, targetLanguage, targetLang, text)
-
-	resp, err := client.CreateChatCompletion(
-		context.Background(),
+	resp, err := t.client.CreateChatCompletion(
+		ctx,
 		openai.ChatCompletionRequest{
-			Model: openai.GPT4o,
+			Model: t.model,
 			Messages: []openai.ChatCompletionMessage{
 				{
 					Role:    openai.ChatMessageRoleUser,
 					Content: prompt,
 				},
 			},
-			Temperature: 0.3,
+			Temperature: t.temperature,
 		},
 	)
 
+	// 응답의 x-ratelimit-* 헤더와 실제 사용량으로 속도 제한을 맞춘다
+	t.limiter.adapt(headers)
 	if err != nil {
-		return "", fmt.Errorf("Translation error: %v", err)
+		settleRateReservation(ctx, 0)
+		retryAfter := parseRetryAfter(headers)
+		if httpStatusCode(err) == http.StatusTooManyRequests {
+			t.limiter.pause(retryAfter)
+		}
+		return "", &providerError{
+			StatusCode: httpStatusCode(err),
+			RetryAfter: retryAfter,
+			Err:        fmt.Errorf("Translation error: %w", err),
+		}
+	}
+	settleRateReservation(ctx, int64(resp.Usage.TotalTokens))
+	recordTokenUsage(ctx, resp.Usage)
+	if len(resp.Choices) == 0 {
+		return "", &invalidOutputError{msg: "empty response from model"}
 	}
 
 	log.Printf("응답: %s", resp.Choices[0].Message.Content)
@@ -568,7 +759,7 @@ Source JSON to translate:
 
 	// JSON 유효성 검사
 	if !json.Valid([]byte(response)) {
-		return "", fmt.Errorf("Invalid JSON structure in response: %s", response)
+		return "", &invalidOutputError{msg: fmt.Sprintf("Invalid JSON structure in response: %s", response)}
 	}
 
 	// JSON 포맷팅
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const DEFAULT_TM_FILE = ".i18n/tm.db"
+
+var tmBucket = []byte("translations")
+
+// 번역 메모리 항목
+type tmEntry struct {
+	Source      string    `json:"source"` // 정규화된 원문
+	Translation string    `json:"translation"`
+	SourceLang  string    `json:"sourceLang"`
+	TargetLang  string    `json:"targetLang"`
+	Model       string    `json:"model"`      // 번역 백엔드와 모델 (예: "openai:gpt-4o")
+	PromptHash  string    `json:"promptHash"` // 프롬프트 템플릿 해시 (기계 번역 API는 빈 값)
+	CreatedAt   time.Time `json:"createdAt"`
+	LastUsedAt  time.Time `json:"lastUsedAt"`
+	Hits        int       `json:"hits"`
+}
+
+func (e *tmEntry) key() []byte {
+	return []byte(hashBytes([]byte(strings.Join([]string{e.SourceLang, e.TargetLang, e.Model, e.PromptHash, e.Source}, "\x00"))))
+}
+
+// 로컬 번역 메모리 (BoltDB 파일)
+// 같은 원문·언어·모델·프롬프트로 이미 검증을 통과한 번역이 있으면 백엔드를 호출하지 않고 재사용한다.
+type translationMemory struct {
+	db *bolt.DB
+}
+
+func openTranslationMemory(path string) (*translationMemory, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("error creating translation memory directory: %v", err)
+	}
+
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening translation memory %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(tmBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error initializing translation memory: %v", err)
+	}
+	return &translationMemory{db: db}, nil
+}
+
+func (tm *translationMemory) Close() error {
+	return tm.db.Close()
+}
+
+var horizontalSpace = regexp.MustCompile(`[ \t]+`)
+
+// 원문 정규화: 앞뒤 공백을 떼고 연속된 공백/탭을 하나로 합친다. (줄바꿈은 유지)
+// 떼어 낸 앞뒤 공백은 조회 결과에 다시 붙일 수 있도록 함께 반환한다.
+func normalizeSource(text string) (normalized, leading, trailing string) {
+	trimmed := strings.TrimSpace(text)
+	start := strings.Index(text, trimmed)
+	leading = text[:start]
+	trailing = text[start+len(trimmed):]
+	return horizontalSpace.ReplaceAllString(trimmed, " "), leading, trailing
+}
+
+// 원문 번역을 찾는다. 찾으면 원문의 앞뒤 공백을 그대로 붙여 반환하고 사용 기록을 갱신한다.
+func (tm *translationMemory) Lookup(text, sourceLang, targetLang, model, promptHash string) (string, bool) {
+	translation, key, found := tm.find(text, sourceLang, targetLang, model, promptHash)
+	if !found {
+		return "", false
+	}
+
+	// 사용 기록 갱신 (prune 기준). 여러 고루틴의 갱신을 Batch로 묶는다.
+	tm.db.Batch(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(tmBucket)
+		var current tmEntry
+		if data := bucket.Get(key); data == nil || json.Unmarshal(data, &current) != nil {
+			return nil
+		}
+		current.LastUsedAt = time.Now()
+		current.Hits++
+		data, err := json.Marshal(&current)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(key, data)
+	})
+
+	return translation, true
+}
+
+// 사용 기록(Hits, LastUsedAt)을 바꾸지 않고 원문 번역을 찾는다 (dry-run과 예산 추정용).
+func (tm *translationMemory) Peek(text, sourceLang, targetLang, model, promptHash string) (string, bool) {
+	translation, _, found := tm.find(text, sourceLang, targetLang, model, promptHash)
+	return translation, found
+}
+
+func (tm *translationMemory) find(text, sourceLang, targetLang, model, promptHash string) (string, []byte, bool) {
+	normalized, leading, trailing := normalizeSource(text)
+	probe := &tmEntry{Source: normalized, SourceLang: sourceLang, TargetLang: targetLang, Model: model, PromptHash: promptHash}
+	key := probe.key()
+
+	var entry tmEntry
+	found := false
+	tm.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(tmBucket).Get(key)
+		if data != nil && json.Unmarshal(data, &entry) == nil {
+			found = true
+		}
+		return nil
+	})
+	if !found {
+		return "", key, false
+	}
+	return leading + entry.Translation + trailing, key, true
+}
+
+// 검증을 통과한 번역을 저장한다. 번역의 앞뒤 공백은 원문과 같은 방식으로 떼어 낸다.
+func (tm *translationMemory) Store(source, translation, sourceLang, targetLang, model, promptHash string) error {
+	normalized, _, _ := normalizeSource(source)
+	now := time.Now()
+	entry := &tmEntry{
+		Source:      normalized,
+		Translation: strings.TrimSpace(translation),
+		SourceLang:  sourceLang,
+		TargetLang:  targetLang,
+		Model:       model,
+		PromptHash:  promptHash,
+		CreatedAt:   now,
+		LastUsedAt:  now,
+	}
+	return tm.db.Batch(func(tx *bolt.Tx) error {
+		return putEntry(tx.Bucket(tmBucket), entry)
+	})
+}
+
+func putEntry(bucket *bolt.Bucket, entry *tmEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(entry.key(), data)
+}
+
+// 모든 항목을 JSON Lines로 내보낸다.
+func (tm *translationMemory) Export(w io.Writer) (int, error) {
+	count := 0
+	err := tm.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(tmBucket).ForEach(func(_, data []byte) error {
+			if _, err := w.Write(append(data, '\n')); err != nil {
+				return err
+			}
+			count++
+			return nil
+		})
+	})
+	return count, err
+}
+
+// JSON Lines로 된 항목을 가져온다. 같은 키의 항목은 덮어쓴다.
+func (tm *translationMemory) Import(r io.Reader) (int, error) {
+	var entries []*tmEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var entry tmEntry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return 0, fmt.Errorf("line %d: %v", line, err)
+		}
+		if entry.Source == "" || entry.TargetLang == "" {
+			return 0, fmt.Errorf("line %d: source and targetLang are required", line)
+		}
+		entry.Source, _, _ = normalizeSource(entry.Source)
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = time.Now()
+		}
+		if entry.LastUsedAt.IsZero() {
+			entry.LastUsedAt = entry.CreatedAt
+		}
+		entries = append(entries, &entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	err := tm.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(tmBucket)
+		for _, entry := range entries {
+			if err := putEntry(bucket, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(entries), err
+}
+
+// 정리 조건 (비어 있는 조건은 무시하며, 지정한 조건을 모두 만족하는 항목을 삭제)
+type tmPruneFilter struct {
+	UnusedFor  time.Duration // 마지막 사용 후 경과 시간
+	Model      string
+	TargetLang string
+}
+
+func (f tmPruneFilter) matches(entry *tmEntry, now time.Time) bool {
+	if f.UnusedFor > 0 && now.Sub(entry.LastUsedAt) < f.UnusedFor {
+		return false
+	}
+	if f.Model != "" && entry.Model != f.Model {
+		return false
+	}
+	if f.TargetLang != "" && entry.TargetLang != f.TargetLang {
+		return false
+	}
+	return true
+}
+
+func (tm *translationMemory) Prune(filter tmPruneFilter) (int, error) {
+	now := time.Now()
+	removed := 0
+	err := tm.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(tmBucket)
+		var keys [][]byte
+		err := bucket.ForEach(func(key, data []byte) error {
+			var entry tmEntry
+			if err := json.Unmarshal(data, &entry); err != nil || filter.matches(&entry, now) {
+				keys = append(keys, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		removed = len(keys)
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTestMemory(t *testing.T) *translationMemory {
+	t.Helper()
+	tm, err := openTranslationMemory(filepath.Join(t.TempDir(), "tm.db"))
+	if err != nil {
+		t.Fatalf("open translation memory: %v", err)
+	}
+	t.Cleanup(func() { tm.Close() })
+	return tm
+}
+
+func TestTranslationMemoryLookup(t *testing.T) {
+	tm := openTestMemory(t)
+	if err := tm.Store("Save  changes", " Zapisz zmiany ", "en", "pl", "fake", ""); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		text   string
+		target string
+		want   string
+		found  bool
+	}{
+		{"exact", "Save  changes", "pl", "Zapisz zmiany", true},
+		{"spaces are normalized and kept", "  Save changes\t", "pl", "  Zapisz zmiany\t", true},
+		{"other language", "Save changes", "de", "", false},
+		{"other text", "Save", "pl", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := tm.Lookup(tt.text, "en", tt.target, "fake", "")
+			if got != tt.want || found != tt.found {
+				t.Errorf("got %q, %v, want %q, %v", got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestTranslateChunkedMemoryHitInArray(t *testing.T) {
+	tm := openTestMemory(t)
+	if err := tm.Store("Go", "Idź", "en", "pl", "fake", ""); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	opts := testTranslateOptions(1000)
+	opts.memory = tm
+	translator := &fakeTranslator{}
+	stats := &requestStats{}
+	source := mustParse(t, `{"steps":["Open","Go","Stop","Close"],"x":"Hi"}`)
+	result, _, err := translateChunked(context.Background(), translator, source, "en", "pl", nil, opts, stats)
+	if err != nil {
+		t.Fatalf("translateChunked: %v", err)
+	}
+
+	want := `{"steps":["T(Open)","Idź","T(Stop)","T(Close)"],"x":"T(Hi)"}`
+	if got := mustJSON(t, result); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if stats.MemoryHits() != 1 {
+		t.Errorf("%d memory hits, want 1", stats.MemoryHits())
+	}
+	if len(translator.requests) != 1 || strings.Contains(translator.requests[0], `"Go"`) {
+		t.Errorf("memory hit was requested again: %v", translator.requests)
+	}
+
+	// 새 번역은 메모리에 저장되어 다음 실행에서 재사용된다
+	if got, ok := tm.Lookup("Stop", "en", "pl", "fake", ""); !ok || got != "T(Stop)" {
+		t.Errorf("stored translation for Stop = %q, %v", got, ok)
+	}
+}
+
+// 저장된 항목의 사용 기록
+func memoryUsage(t *testing.T, tm *translationMemory) (int, time.Time) {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := tm.Export(&buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	var entry tmEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("exported entry: %v", err)
+	}
+	return entry.Hits, entry.LastUsedAt
+}
+
+func TestTranslationMemoryPeekIsReadOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		lookup   func(tm *translationMemory) (string, bool)
+		wantHits int
+	}{
+		{"Peek", func(tm *translationMemory) (string, bool) { return tm.Peek("Go", "en", "pl", "fake", "") }, 0},
+		{"Lookup", func(tm *translationMemory) (string, bool) { return tm.Lookup("Go", "en", "pl", "fake", "") }, 2},
+		{"estimateJob", func(tm *translationMemory) (string, bool) {
+			opts := testTranslateOptions(1000)
+			opts.memory = tm
+			job := TranslationJob{SourceLang: "en", TargetLang: "pl", Content: mustParse(t, `{"a":"Go","b":"Stop"}`)}
+			estimate, err := estimateJob(&fakeTranslator{}, job, opts)
+			if err != nil {
+				t.Fatalf("estimateJob: %v", err)
+			}
+			if estimate.Characters != int64(len("Stop")) {
+				t.Errorf("estimated %d characters, want only the memory miss", estimate.Characters)
+			}
+			return "Idź", true
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := openTestMemory(t)
+			if err := tm.Store("Go", "Idź", "en", "pl", "fake", ""); err != nil {
+				t.Fatalf("Store: %v", err)
+			}
+			_, lastUsed := memoryUsage(t, tm)
+			time.Sleep(10 * time.Millisecond)
+
+			for i := 0; i < 2; i++ {
+				if got, ok := tt.lookup(tm); !ok || got != "Idź" {
+					t.Fatalf("got %q, %v", got, ok)
+				}
+			}
+			hits, used := memoryUsage(t, tm)
+			if hits != tt.wantHits {
+				t.Errorf("hits = %d, want %d", hits, tt.wantHits)
+			}
+			if unchanged := used.Equal(lastUsed); unchanged != (tt.wantHits == 0) {
+				t.Errorf("lastUsedAt %v → %v", lastUsed, used)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// {{name}}, {name}, %s / %d / %1$s, $t(key)
+	// 공백 플래그(% d)는 위치 지정(%1$ d)이 있을 때만 허용한다. "50% discount" 같은 문장을 플레이스홀더로 보지 않기 위해서다.
+	placeholderPattern = regexp.MustCompile(`\{\{[^{}]*\}\}|\{[A-Za-z0-9_$.\-]+\}|%(?:\d+\$[-+ 0#]*|[-+0#]*)\d*(?:\.\d+)?[sdifuxXeEgGc@]|\$t\([^()]*\)`)
+	// <b>, </b>, <br/>, <a href="...">, <Trans>, react-i18next의 <0>...</0>
+	tagPattern = regexp.MustCompile(`<\s*(/?)\s*([A-Za-z][\w.:\-]*|\d+)(?:\s[^<>]*?)?\s*(/?)\s*>`)
+)
+
+// 번역 전후로 보존되어야 하는 서식 요소
+type formatTokens struct {
+	Placeholders map[string]int
+	Tags         map[string]int
+	Newlines     int
+}
+
+func extractFormatTokens(text string) formatTokens {
+	tokens := formatTokens{
+		Placeholders: make(map[string]int),
+		Tags:         make(map[string]int),
+		Newlines:     strings.Count(text, "\n"),
+	}
+
+	for _, match := range placeholderPattern.FindAllString(text, -1) {
+		// {{ name }}과 {{name}}은 같은 플레이스홀더로 취급
+		tokens.Placeholders[strings.Join(strings.Fields(match), "")]++
+	}
+
+	// 속성 값은 번역될 수 있으므로 태그 이름과 열림/닫힘 형태만 비교
+	for _, match := range tagPattern.FindAllStringSubmatch(text, -1) {
+		closing, name, selfClosing := match[1], match[2], match[3]
+		switch {
+		case closing != "":
+			tokens.Tags["</"+name+">"]++
+		case selfClosing != "":
+			tokens.Tags["<"+name+"/>"]++
+		default:
+			tokens.Tags["<"+name+">"]++
+		}
+	}
+	return tokens
+}
+
+// ICU 메시지용: 단일 중괄호 인자를 빼고 각 서식 요소를 한 번씩만 센다.
+func (t formatTokens) forICU() formatTokens {
+	result := formatTokens{Placeholders: make(map[string]int), Tags: make(map[string]int)}
+	for token := range t.Placeholders {
+		if strings.HasPrefix(token, "{") && !strings.HasPrefix(token, "{{") {
+			continue
+		}
+		result.Placeholders[token] = 1
+	}
+	for token := range t.Tags {
+		result.Tags[token] = 1
+	}
+	if t.Newlines > 0 {
+		result.Newlines = 1
+	}
+	return result
+}
+
+// 번역된 값에서 보존되지 않은 서식 요소, 용어집 위반, 길이 초과
+type placeholderIssue struct {
+	Path     string
+	Problems []string
+
+	Value     string // 길이를 검사한 번역
+	Length    int
+	MaxLength int // 0이면 길이 제한 없음
+}
+
+// 길이 제한을 넘었는지
+func (i placeholderIssue) overflow() bool {
+	return i.MaxLength > 0 && i.Length > i.MaxLength
+}
+
+func (i placeholderIssue) String() string {
+	problems := i.Problems
+	if i.overflow() {
+		problems = append(problems[:len(problems):len(problems)], fmt.Sprintf("%d characters, limit %d", i.Length, i.MaxLength))
+	}
+	return fmt.Sprintf("%s: %s", i.Path, strings.Join(problems, ", "))
+}
+
+// 소스 값과 번역 값의 플레이스홀더, 태그, 줄바꿈 수를 비교한다. 문제가 없으면 nil을 반환한다.
+// ICU plural/select 메시지는 대상 언어에 따라 선택지 수가 달라지므로 개수 대신 종류만 비교하고,
+// {name} 인자는 선택지 본문({item})과 구분할 수 없으므로 ICU 검사(checkICUMessage)에 맡긴다.
+func checkPlaceholders(path, source, translated string) *placeholderIssue {
+	want := extractFormatTokens(source)
+	got := extractFormatTokens(translated)
+	if isICUMessage(source) {
+		want, got = want.forICU(), got.forICU()
+	}
+
+	var problems []string
+	problems = append(problems, compareTokenCounts("placeholder", want.Placeholders, got.Placeholders)...)
+	problems = append(problems, compareTokenCounts("tag", want.Tags, got.Tags)...)
+	if want.Newlines != got.Newlines {
+		problems = append(problems, fmt.Sprintf("newline count %d, expected %d", got.Newlines, want.Newlines))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &placeholderIssue{Path: path, Problems: problems}
+}
+
+// 플레이스홀더 검사에 ICU 메시지 검사, 용어집 검사, 길이 제한(maxLength가 0이면 없음) 검사를 더한 번역 값 검사
+func checkTranslatedValue(path, source, translated, targetLang string, g *glossary, maxLength int) *placeholderIssue {
+	issue := checkPlaceholders(path, source, translated)
+	if issue == nil {
+		issue = &placeholderIssue{Path: path}
+	}
+	if isICUMessage(source) {
+		issue.Problems = append(issue.Problems, checkICUMessage(source, translated, targetLang)...)
+	}
+	issue.Problems = append(issue.Problems, g.check(source, translated, targetLang)...)
+	if maxLength > 0 {
+		issue.Value = translated
+		issue.Length = visibleLength(translated)
+		issue.MaxLength = maxLength
+	}
+	if len(issue.Problems) == 0 && !issue.overflow() {
+		return nil
+	}
+	return issue
+}
+
+func compareTokenCounts(kind string, want, got map[string]int) []string {
+	var problems []string
+	for token, count := range want {
+		if got[token] < count {
+			problems = append(problems, fmt.Sprintf("missing %s %s", kind, token))
+		}
+	}
+	for token, count := range got {
+		if want[token] < count {
+			problems = append(problems, fmt.Sprintf("unexpected %s %s", kind, token))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
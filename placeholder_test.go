@@ -0,0 +1,73 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestExtractPlaceholders(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Hello {{name}}, you have {count} items", []string{"{count}", "{{name}}"}},
+		{"{{ name }} and {{name}}", []string{"{{name}}", "{{name}}"}},
+		{"%s of %d, %1$s %2$d %.2f %05.1f %-5s %@", []string{"%-5s", "%.2f", "%05.1f", "%1$s", "%2$d", "%@", "%d", "%s"}},
+		{"positional with space flag %1$ d", []string{"%1$d"}},
+		{"See $t(common.ok)", []string{"$t(common.ok)"}},
+		// 문장 속의 퍼센트 기호는 플레이스홀더가 아니다
+		{"50% discount", nil},
+		{"100% done", nil},
+		{"Save 20% instantly, 10% extra", nil},
+		{"% of users", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got []string
+			for token, count := range extractFormatTokens(tt.text).Placeholders {
+				for i := 0; i < count; i++ {
+					got = append(got, token)
+				}
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		translated string
+		want       []string // 비어 있으면 문제 없음
+	}{
+		{"unchanged placeholders", "Hi {{name}}, %d new", "Cześć {{name}}, %d nowe", nil},
+		{"reordered placeholders", "{a} and {b}", "{b} i {a}", nil},
+		{"missing placeholder", "Hi {{name}}", "Cześć", []string{"missing placeholder {{name}}"}},
+		{"translated placeholder", "Hi {name}", "Cześć {imie}", []string{"missing placeholder {name}", "unexpected placeholder {imie}"}},
+		{"tags kept with translated attributes", `<a href="/x" title="Open">Open</a>`, `<a href="/x" title="Otwórz">Otwórz</a>`, nil},
+		{"missing closing tag", "<b>Bold</b> text", "<b>Pogrubiony tekst", []string{"missing tag </b>"}},
+		{"react-i18next numbered tags", "<0>Click</0> here", "<0>Kliknij</0> tutaj", nil},
+		{"newline count", "a\nb", "a b", []string{"newline count 0, expected 1"}},
+		// 퍼센트 기호를 다르게 옮겨도 플레이스홀더 불일치가 아니다
+		{"reworded percent", "50% discount", "zniżka 50 %", nil},
+		{"percent moved", "100% done", "Gotowe w 100%", nil},
+		{"ICU plural with different option count", "{n, plural, one {# item} other {# items}}", "{n, plural, one {# element} few {# elementy} many {# elementów} other {# elementu}}", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := checkPlaceholders("/k", tt.source, tt.translated)
+			var got []string
+			if issue != nil {
+				got = issue.Problems
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
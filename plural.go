@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// i18next 복수형 키 (예: item_one, item_other, place_ordinal_two)
+var pluralKeyPattern = regexp.MustCompile(`^(.+?)_(ordinal_)?(zero|one|two|few|many|other)$`)
+
+// 같은 객체 안의 복수형 키 묶음
+type pluralGroup struct {
+	base    string
+	ordinal bool
+	forms   map[string]interface{} // 범주 → 소스 값
+}
+
+func (g *pluralGroup) key(category string) string {
+	if g.ordinal {
+		return g.base + "_ordinal_" + category
+	}
+	return g.base + "_" + category
+}
+
+// 범주의 번역 기준 문장 (소스에 없는 범주는 other)
+func (g *pluralGroup) seed(category string) interface{} {
+	if value, ok := g.forms[category]; ok {
+		return value
+	}
+	return g.forms["other"]
+}
+
+// 소스의 i18next 복수형 키를 대상 언어의 CLDR 범주에 맞게 바꾼다.
+// 대상 언어에 필요한 범주 키(예: 폴란드어 _few, _many)를 추가하고, 쓰지 않는 범주 키(예: 일본어 _one)는 뺀다.
+// forms는 범주 이름을 키로 갖는 복수형 객체(Android plurals, stringsdict, Rails YAML 등)의 경로이며, 같은 방식으로 범주를 맞춘다.
+// 추가된 키는 어떤 복수형인지 모델에 알려 주는 맥락과 함께 반환한다.
+func expandPluralKeys(content interface{}, sourceLang, targetLang string, forms map[string]bool) (interface{}, contextNotes) {
+	notes := make(contextNotes)
+	return expandPluralNode(content, "", sourceLang, targetLang, forms, notes), notes
+}
+
+func expandPluralNode(node interface{}, path, sourceLang, targetLang string, forms map[string]bool, notes contextNotes) interface{} {
+	switch v := node.(type) {
+	case *orderedMap:
+		if forms[path] {
+			return expandPluralForms(v, path, sourceLang, targetLang, notes)
+		}
+		groups := findPluralGroups(v)
+		result := newOrderedMap()
+		emitted := make(map[*pluralGroup]bool)
+		for _, key := range v.Keys() {
+			value, _ := v.Get(key)
+			group := groups[key]
+			if group == nil {
+				result.Set(key, expandPluralNode(value, joinPointer(path, key), sourceLang, targetLang, forms, notes))
+				continue
+			}
+			// 묶음의 첫 키 위치에 대상 언어 범주 순서대로 넣는다
+			if emitted[group] {
+				continue
+			}
+			emitted[group] = true
+			for _, category := range cldrPluralCategories(targetLang, group.ordinal) {
+				targetKey := group.key(category)
+				result.Set(targetKey, group.seed(category))
+				if _, ok := group.forms[category]; !ok {
+					kind := "plural"
+					if group.ordinal {
+						kind = "ordinal plural"
+					}
+					notes[joinPointer(path, targetKey)] = keyContext{
+						Description: fmt.Sprintf("i18next %s form %q for %s; the source text is the %s \"other\" form, adapt it to this category", kind, category, languageName(targetLang), languageName(sourceLang)),
+					}
+				}
+			}
+		}
+		return result
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = expandPluralNode(item, joinPointer(path, fmt.Sprint(i)), sourceLang, targetLang, forms, notes)
+		}
+		return items
+	}
+	return node
+}
+
+// 범주 이름을 키로 갖는 복수형 객체를 대상 언어의 범주로 바꾼다. 범주가 아닌 키(stringsdict의 형식 키 등)는 앞에 그대로 둔다.
+func expandPluralForms(m *orderedMap, path, sourceLang, targetLang string, notes contextNotes) *orderedMap {
+	result := newOrderedMap()
+	for _, key := range m.Keys() {
+		if !validPluralCategory[key] {
+			value, _ := m.Get(key)
+			result.Set(key, value)
+		}
+	}
+	for _, category := range cldrPluralCategories(targetLang, false) {
+		value, ok := m.Get(category)
+		if !ok {
+			value, _ = m.Get("other")
+			notes[joinPointer(path, category)] = keyContext{
+				Description: fmt.Sprintf("plural form %q for %s; the source text is the %s \"other\" form, adapt it to this category", category, languageName(targetLang), languageName(sourceLang)),
+			}
+		}
+		result.Set(category, value)
+	}
+	return result
+}
+
+// 범주 이름을 키로 갖는 복수형 객체인지 확인한다. "other"와 다른 범주가 하나 이상 있어야 한다.
+func isPluralForms(m *orderedMap) bool {
+	categories := 0
+	for _, key := range m.Keys() {
+		value, _ := m.Get(key)
+		if _, ok := value.(string); ok && validPluralCategory[key] {
+			categories++
+		}
+	}
+	_, hasOther := m.Get("other")
+	return hasOther && categories >= 2
+}
+
+// 객체에서 복수형 키 묶음을 찾는다. "_other"와 다른 범주가 하나 이상 있는 문자열 키만 묶음으로 본다.
+func findPluralGroups(m *orderedMap) map[string]*pluralGroup {
+	candidates := make(map[string]*pluralGroup)
+	var order []*pluralGroup
+	for _, key := range m.Keys() {
+		match := pluralKeyPattern.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+		value, _ := m.Get(key)
+		if _, ok := value.(string); !ok {
+			continue
+		}
+		id := match[1] + "\x00" + match[2]
+		group := candidates[id]
+		if group == nil {
+			group = &pluralGroup{base: match[1], ordinal: match[2] != "", forms: make(map[string]interface{})}
+			candidates[id] = group
+			order = append(order, group)
+		}
+		group.forms[match[3]] = value
+	}
+
+	groups := make(map[string]*pluralGroup)
+	for _, group := range order {
+		if _, ok := group.forms["other"]; !ok || len(group.forms) < 2 {
+			continue
+		}
+		for category := range group.forms {
+			groups[group.key(category)] = group
+		}
+	}
+	return groups
+}
+
+func languageName(lang string) string {
+	if name := languageMap[lang]; name != "" {
+		return name
+	}
+	return lang
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindPluralGroups(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   map[string]string // 키 → 묶음 (base, 서수면 "base (ordinal)")
+	}{
+		{
+			name:   "cardinal group",
+			source: `{"item_one":"1 item","item_other":"{{count}} items","title":"Title"}`,
+			want:   map[string]string{"item_one": "item", "item_other": "item"},
+		},
+		{
+			name:   "lone _one without _other is not a group",
+			source: `{"foo_one":"One foo","bar_other":"Bars"}`,
+			want:   map[string]string{},
+		},
+		{
+			name:   "ordinal group is separate from the cardinal group",
+			source: `{"place_one":"1 place","place_other":"places","place_ordinal_one":"1st","place_ordinal_two":"2nd","place_ordinal_other":"nth"}`,
+			want: map[string]string{
+				"place_one": "place", "place_other": "place",
+				"place_ordinal_one": "place (ordinal)", "place_ordinal_two": "place (ordinal)", "place_ordinal_other": "place (ordinal)",
+			},
+		},
+		{
+			name:   "base with underscores",
+			source: `{"new_message_one":"1 new message","new_message_other":"new messages"}`,
+			want:   map[string]string{"new_message_one": "new_message", "new_message_other": "new_message"},
+		},
+		{
+			name:   "non-string values are ignored",
+			source: `{"item_one":{"a":"b"},"item_other":"items"}`,
+			want:   map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for key, group := range findPluralGroups(mustParse(t, tt.source).(*orderedMap)) {
+				got[key] = group.base
+				if group.ordinal {
+					got[key] += " (ordinal)"
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPluralKeys(t *testing.T) {
+	const cardinal = `{"title":"Inbox","item_one":"{{count}} item","item_other":"{{count}} items","done":true}`
+	const ordinal = `{"place_ordinal_one":"{{count}}st","place_ordinal_two":"{{count}}nd","place_ordinal_few":"{{count}}rd","place_ordinal_other":"{{count}}th"}`
+	tests := []struct {
+		name      string
+		source    string
+		target    string
+		forms     map[string]bool
+		want      string
+		wantNotes []string
+	}{
+		{
+			name:      "pl adds _few and _many",
+			source:    cardinal,
+			target:    "pl",
+			want:      `{"title":"Inbox","item_one":"{{count}} item","item_few":"{{count}} items","item_many":"{{count}} items","item_other":"{{count}} items","done":true}`,
+			wantNotes: []string{"/item_few", "/item_many"},
+		},
+		{
+			name:   "ja drops _one",
+			source: cardinal,
+			target: "ja",
+			want:   `{"title":"Inbox","item_other":"{{count}} items","done":true}`,
+		},
+		{
+			name:      "ar adds _zero, _two, _few and _many",
+			source:    cardinal,
+			target:    "ar",
+			want:      `{"title":"Inbox","item_zero":"{{count}} items","item_one":"{{count}} item","item_two":"{{count}} items","item_few":"{{count}} items","item_many":"{{count}} items","item_other":"{{count}} items","done":true}`,
+			wantNotes: []string{"/item_few", "/item_many", "/item_two", "/item_zero"},
+		},
+		{
+			name:   "same categories are unchanged",
+			source: cardinal,
+			target: "de",
+			want:   cardinal,
+		},
+		{
+			name:   "ordinal groups use ordinal categories",
+			source: ordinal,
+			target: "fr",
+			want:   `{"place_ordinal_one":"{{count}}st","place_ordinal_other":"{{count}}th"}`,
+		},
+		{
+			name:   "ordinal groups in a language with only other",
+			source: ordinal,
+			target: "pl",
+			want:   `{"place_ordinal_other":"{{count}}th"}`,
+		},
+		{
+			name:      "ordinal keys are added from other",
+			source:    `{"place_ordinal_one":"{{count}}er","place_ordinal_other":"{{count}}e"}`,
+			target:    "en",
+			want:      `{"place_ordinal_one":"{{count}}er","place_ordinal_two":"{{count}}e","place_ordinal_few":"{{count}}e","place_ordinal_other":"{{count}}e"}`,
+			wantNotes: []string{"/place_ordinal_few", "/place_ordinal_two"},
+		},
+		{
+			name:   "lone _one without _other is left alone",
+			source: `{"foo_one":"One foo","bar":"Bar"}`,
+			target: "pl",
+			want:   `{"foo_one":"One foo","bar":"Bar"}`,
+		},
+		{
+			name:   "nested objects and arrays",
+			source: `{"inbox":{"msg_one":"1 message","msg_other":"messages"},"list":[{"x_one":"1 x","x_other":"xs"}]}`,
+			target: "ja",
+			want:   `{"inbox":{"msg_other":"messages"},"list":[{"x_other":"xs"}]}`,
+		},
+		{
+			name:      "plural forms objects",
+			source:    `{"files":{"one":"%d file","other":"%d files"}}`,
+			target:    "pl",
+			forms:     map[string]bool{"/files": true},
+			want:      `{"files":{"one":"%d file","few":"%d files","many":"%d files","other":"%d files"}}`,
+			wantNotes: []string{"/files/few", "/files/many"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, notes := expandPluralKeys(mustParse(t, tt.source), "en", tt.target, tt.forms)
+			if json := mustJSON(t, got); json != tt.want {
+				t.Errorf("got %s, want %s", json, tt.want)
+			}
+			var paths []string
+			for path, note := range notes {
+				paths = append(paths, path)
+				if !strings.Contains(note.Description, languageName(tt.target)) || !strings.Contains(note.Description, `"other" form`) {
+					t.Errorf("%s: note %q", path, note.Description)
+				}
+			}
+			sort.Strings(paths)
+			if !reflect.DeepEqual(paths, tt.wantNotes) {
+				t.Errorf("notes for %v, want %v", paths, tt.wantNotes)
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gettext PO/POT
+// 키는 msgid(msgctxt가 있으면 "msgctxt|msgid")이고 값은 소스 파일의 msgid다.
+// msgid_plural이 있는 항목은 {"one": msgid, "other": msgid_plural} 복수형 객체가 되며,
+// 대상 파일에서는 대상 언어의 Plural-Forms 순서에 맞춰 msgstr[n]으로 쓴다.
+// 추출 주석(#.)과 msgctxt는 맥락으로 모델에 전달한다.
+type poFormat struct{}
+
+type poEntry struct {
+	comments     []string // "#"로 시작하는 줄 (번역자 주석, 추출 주석, 참조, 플래그)
+	context      *string
+	id           string
+	plural       *string
+	translations []string // msgstr 또는 msgstr[n]
+	obsolete     string   // "#~" 항목은 원문 그대로 둔다
+}
+
+func (e *poEntry) key() string {
+	if e.context != nil {
+		return *e.context + "|" + e.id
+	}
+	return e.id
+}
+
+func (e *poEntry) fuzzy() bool {
+	for _, comment := range e.comments {
+		if strings.HasPrefix(comment, "#,") && strings.Contains(comment, "fuzzy") {
+			return true
+		}
+	}
+	return false
+}
+
+// gettext Plural-Forms 헤더와 msgstr 인덱스별 CLDR 범주
+type poPluralRule struct {
+	header     string
+	categories []string
+}
+
+var (
+	poPluralOneOther = poPluralRule{"nplurals=2; plural=(n != 1);", []string{"one", "other"}}
+	poPluralFrench   = poPluralRule{"nplurals=2; plural=(n > 1);", []string{"one", "other"}}
+	poPluralNone     = poPluralRule{"nplurals=1; plural=0;", []string{"other"}}
+	poPluralSlavic   = poPluralRule{"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);", []string{"one", "few", "many"}}
+	poPluralCzech    = poPluralRule{"nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;", []string{"one", "few", "other"}}
+)
+
+var poPluralRules = map[string]poPluralRule{
+	"ar":    {"nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);", []string{"zero", "one", "two", "few", "many", "other"}},
+	"cs":    poPluralCzech,
+	"sk":    poPluralCzech,
+	"fr":    poPluralFrench,
+	"pt-BR": poPluralFrench,
+	"hi":    poPluralFrench,
+	"fa":    poPluralFrench,
+	"hr":    {poPluralSlavic.header, []string{"one", "few", "other"}},
+	"ru":    poPluralSlavic,
+	"uk":    poPluralSlavic,
+	"pl":    {"nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);", []string{"one", "few", "many"}},
+	"lt":    {"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2);", []string{"one", "few", "other"}},
+	"lv":    {"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2);", []string{"one", "other", "zero"}},
+	"ro":    {"nplurals=3; plural=(n==1 ? 0 : (n==0 || (n%100 > 0 && n%100 < 20)) ? 1 : 2);", []string{"one", "few", "other"}},
+	"is":    {"nplurals=2; plural=(n%10!=1 || n%100==11);", []string{"one", "other"}},
+	"he":    poPluralOneOther,
+	"es":    poPluralOneOther,
+	"it":    poPluralOneOther,
+	"ca":    poPluralOneOther,
+	"pt":    poPluralOneOther,
+	"ja":    poPluralNone,
+	"ko":    poPluralNone,
+	"zh":    poPluralNone,
+	"zh-TW": poPluralNone,
+	"vi":    poPluralNone,
+	"th":    poPluralNone,
+	"id":    poPluralNone,
+	"ms":    poPluralNone,
+	"km":    poPluralNone,
+	"lo":    poPluralNone,
+	"my":    poPluralNone,
+}
+
+// 언어의 Plural-Forms 규칙 (표에 없으면 CLDR 범주가 둘이면 n != 1, 아니면 소스 헤더를 유지하고 CLDR 순서를 쓴다)
+func poPluralRuleFor(lang string) (poPluralRule, bool) {
+	if rule, ok := poPluralRules[lang]; ok {
+		return rule, true
+	}
+	categories := cldrPluralCategories(lang, false)
+	if len(categories) == 2 && categories[0] == "one" {
+		return poPluralOneOther, true
+	}
+	return poPluralRule{categories: categories}, false
+}
+
+func (poFormat) Name() string { return "po" }
+
+func (poFormat) Parse(data []byte, lang string) (*localeDocument, error) {
+	entries, err := parsePO(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &localeDocument{Content: newOrderedMap(), Context: make(contextNotes), Plurals: make(map[string]bool), source: entries}
+	content := doc.Content.(*orderedMap)
+	for _, entry := range entries {
+		if entry.obsolete != "" || entry.id == "" {
+			continue
+		}
+		path := joinPointer("", entry.key())
+		if entry.plural != nil {
+			forms := newOrderedMap()
+			forms.Set("one", entry.id)
+			forms.Set("other", *entry.plural)
+			content.Set(entry.key(), forms)
+			doc.Plurals[path] = true
+		} else {
+			content.Set(entry.key(), entry.id)
+		}
+
+		var notes []string
+		for _, comment := range entry.comments {
+			if strings.HasPrefix(comment, "#.") {
+				notes = append(notes, strings.TrimSpace(strings.TrimPrefix(comment, "#.")))
+			}
+		}
+		if entry.context != nil {
+			notes = append(notes, "gettext context: "+*entry.context)
+		}
+		if len(notes) > 0 {
+			doc.Context[path] = keyContext{Description: strings.Join(notes, " ")}
+		}
+	}
+	return doc, nil
+}
+
+// 기존 대상 파일의 msgstr (비어 있거나 fuzzy인 항목은 번역되지 않은 것으로 본다)
+func (poFormat) ReadTarget(data []byte, lang string) (interface{}, error) {
+	entries, err := parsePO(data)
+	if err != nil {
+		return nil, err
+	}
+	rule, _ := poPluralRuleFor(lang)
+
+	content := newOrderedMap()
+	for _, entry := range entries {
+		if entry.obsolete != "" || entry.id == "" || entry.fuzzy() {
+			continue
+		}
+		if entry.plural == nil {
+			if len(entry.translations) > 0 && entry.translations[0] != "" {
+				content.Set(entry.key(), entry.translations[0])
+			}
+			continue
+		}
+		forms := newOrderedMap()
+		for i, category := range rule.categories {
+			if i < len(entry.translations) && entry.translations[i] != "" {
+				forms.Set(category, entry.translations[i])
+			}
+		}
+		if forms.Len() != len(rule.categories) {
+			continue
+		}
+		// PO에 없는 CLDR 범주(폴란드어 other 등)는 마지막 형태로 채워 소스 구조와 맞춘다
+		last, _ := lookupString(forms, rule.categories[len(rule.categories)-1])
+		for _, category := range cldrPluralCategories(lang, false) {
+			if _, ok := forms.Get(category); !ok {
+				forms.Set(category, last)
+			}
+		}
+		content.Set(entry.key(), forms)
+	}
+	return content, nil
+}
+
+func (poFormat) Render(doc *localeDocument, content interface{}, lang string) ([]byte, error) {
+	entries := doc.source.([]*poEntry)
+	translated, _ := content.(*orderedMap)
+	if translated == nil {
+		translated = newOrderedMap()
+	}
+	rule, known := poPluralRuleFor(lang)
+
+	var buf bytes.Buffer
+	for i, entry := range entries {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		if entry.obsolete != "" {
+			buf.WriteString(entry.obsolete)
+			continue
+		}
+		for _, comment := range targetPOComments(entry.comments) {
+			buf.WriteString(comment + "\n")
+		}
+		if entry.context != nil {
+			writePOString(&buf, "msgctxt", *entry.context)
+		}
+		writePOString(&buf, "msgid", entry.id)
+
+		if entry.id == "" {
+			// 헤더: 언어와 복수형 규칙을 대상 언어로 바꾼다
+			header := ""
+			if len(entry.translations) > 0 {
+				header = entry.translations[0]
+			}
+			header = setPOHeader(header, "Language", lang)
+			if known {
+				header = setPOHeader(header, "Plural-Forms", rule.header)
+			}
+			writePOString(&buf, "msgstr", header)
+			continue
+		}
+
+		value, _ := translated.Get(entry.key())
+		if entry.plural == nil {
+			text, _ := value.(string)
+			writePOString(&buf, "msgstr", text)
+			continue
+		}
+		writePOString(&buf, "msgid_plural", *entry.plural)
+		forms, _ := value.(*orderedMap)
+		for n, category := range rule.categories {
+			text := ""
+			if forms != nil {
+				text, _ = lookupString(forms, category)
+			}
+			writePOString(&buf, fmt.Sprintf("msgstr[%d]", n), text)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// 대상 파일에 남길 주석: 새로 번역했으므로 fuzzy 플래그와 이전 msgid(#|)는 뺀다.
+func targetPOComments(comments []string) []string {
+	var result []string
+	for _, comment := range comments {
+		if strings.HasPrefix(comment, "#|") {
+			continue
+		}
+		if strings.HasPrefix(comment, "#,") {
+			var flags []string
+			for _, flag := range strings.Split(strings.TrimPrefix(comment, "#,"), ",") {
+				if flag = strings.TrimSpace(flag); flag != "" && flag != "fuzzy" {
+					flags = append(flags, flag)
+				}
+			}
+			if len(flags) == 0 {
+				continue
+			}
+			comment = "#, " + strings.Join(flags, ", ")
+		}
+		result = append(result, comment)
+	}
+	return result
+}
+
+// 헤더 필드 값을 바꾸거나 추가한다.
+func setPOHeader(header, field, value string) string {
+	lines := strings.Split(strings.TrimSuffix(header, "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, field+":") {
+			lines[i] = field + ": " + value
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+	if header == "" {
+		return field + ": " + value + "\n"
+	}
+	return strings.Join(append(lines, field+": "+value), "\n") + "\n"
+}
+
+// 키워드와 문자열을 쓴다. 줄바꿈이 있으면 gettext처럼 줄마다 나눠 쓴다.
+func writePOString(buf *bytes.Buffer, keyword, value string) {
+	lines := strings.SplitAfter(value, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= 1 {
+		fmt.Fprintf(buf, "%s %s\n", keyword, quotePO(value))
+		return
+	}
+	fmt.Fprintf(buf, "%s \"\"\n", keyword)
+	for _, line := range lines {
+		buf.WriteString(quotePO(line) + "\n")
+	}
+}
+
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func quotePO(s string) string {
+	return `"` + poEscaper.Replace(s) + `"`
+}
+
+func unquotePO(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("expected a quoted string: %s", s)
+	}
+	var b strings.Builder
+	body := s[1 : len(s)-1]
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c != '\\' || i+1 == len(body) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch body[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(body[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// PO 파일을 항목 목록으로 파싱한다. 빈 줄이 항목을 구분한다.
+func parsePO(data []byte) ([]*poEntry, error) {
+	var entries []*poEntry
+	entry := &poEntry{}
+	var target *string // 이어지는 문자열 줄이 붙을 필드
+	started := false
+
+	flush := func() {
+		if started {
+			entries = append(entries, entry)
+		}
+		entry, target, started = &poEntry{}, nil, false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#~"):
+			if started && entry.obsolete == "" {
+				flush()
+			}
+			entry.obsolete += scanner.Text() + "\n"
+			started = true
+		case strings.HasPrefix(line, "#"):
+			if entry.obsolete != "" || target != nil {
+				flush()
+			}
+			entry.comments = append(entry.comments, line)
+			started = true
+		case strings.HasPrefix(line, `"`):
+			if target == nil {
+				return nil, fmt.Errorf("line %d: string without a keyword", lineNo)
+			}
+			value, err := unquotePO(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			*target += value
+		default:
+			keyword, rest, _ := strings.Cut(line, " ")
+			value, err := unquotePO(strings.TrimSpace(rest))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			// 빈 줄 없이 이어지는 다음 항목
+			if (keyword == "msgctxt" && target != nil) || (keyword == "msgid" && len(entry.translations) > 0) {
+				flush()
+			}
+			started = true
+			switch {
+			case keyword == "msgctxt":
+				entry.context = &value
+				target = entry.context
+			case keyword == "msgid":
+				entry.id = value
+				target = &entry.id
+			case keyword == "msgid_plural":
+				entry.plural = &value
+				target = entry.plural
+			case keyword == "msgstr":
+				entry.translations = []string{value}
+				target = &entry.translations[0]
+			case strings.HasPrefix(keyword, "msgstr["):
+				n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(keyword, "msgstr["), "]"))
+				if err != nil || n != len(entry.translations) {
+					return nil, fmt.Errorf("line %d: unexpected %s", lineNo, keyword)
+				}
+				entry.translations = append(entry.translations, value)
+				target = &entry.translations[n]
+			default:
+				return nil, fmt.Errorf("line %d: unknown keyword %q", lineNo, keyword)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return entries, nil
+}
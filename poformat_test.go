@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+)
+
+const testPOSource = `# header
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#. greeting
+msgid "Hi"
+msgstr ""
+
+msgctxt "menu"
+msgid "Open"
+msgstr ""
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+`
+
+func TestPOFormatRoundTrip(t *testing.T) {
+	runFormatRoundTrips(t, []formatRoundTrip{
+		{
+			name:        "msgctxt, plural forms and header",
+			path:        "messages.pot",
+			source:      testPOSource,
+			wantContent: `{"Hi":"Hi","menu|Open":"Open","One file":{"one":"One file","other":"%d files"}}`,
+			translated:  `{"Hi":"Salut","menu|Open":"Ouvrir","One file":{"one":"Un fichier","other":"%d fichiers"}}`,
+			want: `# header
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+#. greeting
+msgid "Hi"
+msgstr "Salut"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Ouvrir"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Un fichier"
+msgstr[1] "%d fichiers"
+`,
+			// 프랑스어의 CLDR many 범주는 PO에 없으므로 마지막 형태로 채운다
+			wantRead: `{"Hi":"Salut","menu|Open":"Ouvrir","One file":{"one":"Un fichier","other":"%d fichiers","many":"%d fichiers"}}`,
+		},
+		{
+			name:        "untranslated entries stay empty",
+			path:        "messages.po",
+			source:      testPOSource,
+			wantContent: `{"Hi":"Hi","menu|Open":"Open","One file":{"one":"One file","other":"%d files"}}`,
+			translated:  `{"Hi":"Salut"}`,
+			want: `# header
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+#. greeting
+msgid "Hi"
+msgstr "Salut"
+
+msgctxt "menu"
+msgid "Open"
+msgstr ""
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+`,
+		},
+	})
+}
+
+func TestPOFormatContext(t *testing.T) {
+	doc, err := parseLocaleFile("messages.pot", []byte(testPOSource), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/Hi", "greeting"},
+		{"/menu|Open", "gettext context: menu"},
+	}
+	for _, tt := range tests {
+		if got := doc.Context[tt.path].Description; got != tt.want {
+			t.Errorf("context[%s] = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+	if !doc.Plurals["/One file"] {
+		t.Error("msgid_plural entry is not marked as plural")
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+)
+
+// 모델별 가격 (설정 파일의 pricing, USD)
+// 키는 모델 이름(예: gpt-4o), 백엔드:모델(예: azure:my-deployment) 또는 deepl/google이다.
+type ModelPrice struct {
+	Input      float64 `yaml:"input"`      // 프롬프트 100만 토큰당
+	Output     float64 `yaml:"output"`     // 출력 100만 토큰당
+	Characters float64 `yaml:"characters"` // DeepL/Google: 원문 100만 글자당
+	Encoding   string  `yaml:"encoding"`   // 토크나이저 인코딩 (예: o200k_base), 비우면 모델 이름으로 정한다
+}
+
+type priceTable map[string]ModelPrice
+
+// 이름 순서대로 가격을 찾는다. 정확히 일치하는 키가 없으면 가장 긴 접두사 키를 사용한다
+// (예: gpt-4o-2024-08-06 → gpt-4o).
+func (p priceTable) lookup(names ...string) (ModelPrice, bool) {
+	for _, name := range names {
+		if price, ok := p[name]; ok {
+			return price, true
+		}
+	}
+	for _, name := range names {
+		best := ""
+		for key := range p {
+			if strings.HasPrefix(name, key) && len(key) > len(best) {
+				best = key
+			}
+		}
+		if best != "" {
+			return p[best], true
+		}
+	}
+	return ModelPrice{}, false
+}
+
+func (p ModelPrice) cost(promptTokens, outputTokens, characters int64) float64 {
+	return (float64(promptTokens)*p.Input + float64(outputTokens)*p.Output + float64(characters)*p.Characters) / 1e6
+}
+
+// 번역 백엔드의 가격 (가격표에 없으면 false)
+func priceFor(translator Translator, prices priceTable) (ModelPrice, bool) {
+	if chat, ok := translator.(*chatTranslator); ok {
+		return prices.lookup(translator.Name(), chat.model)
+	}
+	return prices.lookup(translator.Name())
+}
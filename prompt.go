@@ -0,0 +1,144 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// 기본 번역 프롬프트 (prompts/default.tmpl을 바이너리에 포함)
+//
+//go:embed prompts/default.tmpl
+var defaultPromptTemplate string
+
+// 번역 프롬프트 템플릿 (Go text/template)
+type promptTemplate struct {
+	Name string // 파일 경로 또는 "default"
+	tmpl *template.Template
+	hash string // 템플릿 원문 해시
+}
+
+// 템플릿 변수
+type promptData struct {
+	SourceLanguage string // 언어 이름 (예: "English")
+	SourceLang     string // 언어 코드 (예: "en")
+	TargetLanguage string
+	TargetLang     string
+	BrandVoice     string
+	Glossary       []promptGlossaryEntry // 요청에 등장하는 용어만
+	Context        []promptContextNote   // 키별 사용 맥락
+	Payload        string                // 번역할 JSON
+
+	ICU               bool     // 요청에 ICU plural/select 메시지가 있음
+	PluralCategories  []string // 대상 언어의 CLDR 기수 범주
+	OrdinalCategories []string // 대상 언어의 CLDR 서수 범주
+}
+
+type promptGlossaryEntry struct {
+	Term           string
+	Translation    string // 대상 언어의 승인된 번역 (DoNotTranslate면 Term과 같음)
+	DoNotTranslate bool
+}
+
+type promptContextNote struct {
+	Key         string // JSON Pointer 경로
+	Note        string // 설명, 최대 길이, 스크린샷을 합친 한 줄
+	Description string
+	MaxLength   int
+	Screenshot  string
+}
+
+func parsePromptTemplate(name, text string) (*promptTemplate, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing prompt template %s: %v", name, err)
+	}
+	return &promptTemplate{Name: name, tmpl: tmpl, hash: hashBytes([]byte(text))}, nil
+}
+
+func loadPromptTemplate(path string) (*promptTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading prompt template: %v", err)
+	}
+	return parsePromptTemplate(path, string(data))
+}
+
+func (p *promptTemplate) render(data promptData) (string, error) {
+	var b strings.Builder
+	if err := p.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("error rendering prompt template %s: %v", p.Name, err)
+	}
+	return b.String(), nil
+}
+
+// 프롬프트 템플릿 선택 규칙 (설정 파일의 prompt.templates)
+// files와 languages 중 지정한 조건을 모두 만족하면 적용되며, 먼저 나온 규칙이 우선한다.
+type PromptRule struct {
+	Files     []string `yaml:"files"`     // 소스 디렉터리 기준 파일 glob (예: "legal/**")
+	Languages []string `yaml:"languages"` // 대상 언어 코드
+	Template  string   `yaml:"template"`  // 템플릿 파일 경로
+}
+
+func (r *PromptRule) matches(file, lang string) bool {
+	if len(r.Files) > 0 && !matchAnyGlob(r.Files, file) {
+		return false
+	}
+	if len(r.Languages) > 0 {
+		for _, candidate := range r.Languages {
+			if candidate == lang {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// 작업별 프롬프트 템플릿 선택
+type promptSet struct {
+	defaultTemplate *promptTemplate
+	rules           []PromptRule
+	templates       map[string]*promptTemplate // 경로 → 템플릿
+}
+
+// 기본 템플릿(빈 경로면 내장 템플릿)과 규칙의 템플릿을 미리 읽어 문법 오류를 실행 전에 알린다.
+func loadPromptSet(defaultPath string, rules []PromptRule) (*promptSet, error) {
+	set := &promptSet{rules: rules, templates: make(map[string]*promptTemplate)}
+
+	var err error
+	if defaultPath == "" {
+		set.defaultTemplate, err = parsePromptTemplate("default", defaultPromptTemplate)
+	} else {
+		set.defaultTemplate, err = loadPromptTemplate(defaultPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rule := range rules {
+		if rule.Template == "" {
+			return nil, fmt.Errorf("prompt.templates[%d]: template is required", i)
+		}
+		if _, ok := set.templates[rule.Template]; ok {
+			continue
+		}
+		tmpl, err := loadPromptTemplate(rule.Template)
+		if err != nil {
+			return nil, err
+		}
+		set.templates[rule.Template] = tmpl
+	}
+	return set, nil
+}
+
+func (s *promptSet) forJob(job TranslationJob) *promptTemplate {
+	for _, rule := range s.rules {
+		if rule.matches(job.File, job.TargetLang) {
+			return s.templates[rule.Template]
+		}
+	}
+	return s.defaultTemplate
+}
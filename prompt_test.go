@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptRuleMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		rule PromptRule
+		file string
+		lang string
+		want bool
+	}{
+		{name: "no conditions", rule: PromptRule{}, file: "auth.json", lang: "fr", want: true},
+		{name: "file glob", rule: PromptRule{Files: []string{"legal/**"}}, file: "legal/terms/privacy.json", lang: "fr", want: true},
+		{name: "file glob miss", rule: PromptRule{Files: []string{"legal/**"}}, file: "auth.json", lang: "fr", want: false},
+		{name: "any of several globs", rule: PromptRule{Files: []string{"legal/**", "marketing/*.json"}}, file: "marketing/home.json", lang: "fr", want: true},
+		{name: "language", rule: PromptRule{Languages: []string{"ja", "ko"}}, file: "auth.json", lang: "ko", want: true},
+		{name: "language miss", rule: PromptRule{Languages: []string{"ja", "ko"}}, file: "auth.json", lang: "fr", want: false},
+		{name: "file and language", rule: PromptRule{Files: []string{"legal/**"}, Languages: []string{"de"}}, file: "legal/terms.json", lang: "de", want: true},
+		{name: "file matches but language does not", rule: PromptRule{Files: []string{"legal/**"}, Languages: []string{"de"}}, file: "legal/terms.json", lang: "fr", want: false},
+		{name: "language matches but file does not", rule: PromptRule{Files: []string{"legal/**"}, Languages: []string{"de"}}, file: "auth.json", lang: "de", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.matches(tt.file, tt.lang); got != tt.want {
+				t.Errorf("matches(%q, %q) = %v, want %v", tt.file, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptSetForJob(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate := func(name string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name+": {{.TargetLang}}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	legal, legalDE, cjk := writeTemplate("legal.tmpl"), writeTemplate("legal-de.tmpl"), writeTemplate("cjk.tmpl")
+
+	set, err := loadPromptSet("", []PromptRule{
+		{Files: []string{"legal/**"}, Template: legal},
+		{Files: []string{"legal/**"}, Languages: []string{"de"}, Template: legalDE}, // 앞 규칙에 가려짐
+		{Languages: []string{"ja", "zh"}, Template: cjk},
+		{Languages: []string{"ja"}, Template: legal}, // 같은 템플릿은 한 번만 읽는다
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.templates) != 3 {
+		t.Errorf("%d templates loaded, want 3", len(set.templates))
+	}
+
+	tests := []struct {
+		name string
+		file string
+		lang string
+		want string
+	}{
+		{name: "file glob", file: "legal/terms.json", lang: "fr", want: legal},
+		{name: "first matching rule wins", file: "legal/terms.json", lang: "de", want: legal},
+		{name: "file rule before language rule", file: "legal/terms.json", lang: "ja", want: legal},
+		{name: "language rule", file: "auth.json", lang: "ja", want: cjk},
+		{name: "default template", file: "auth.json", lang: "fr", want: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := set.forJob(TranslationJob{File: tt.file, TargetLang: tt.lang}); got.Name != tt.want {
+				t.Errorf("template %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+
+	// 기본 템플릿 파일을 지정하면 규칙에 맞지 않는 작업에 사용한다
+	custom := writeTemplate("custom.tmpl")
+	set, err = loadPromptSet(custom, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt, err := set.forJob(TranslationJob{File: "auth.json", TargetLang: "fr"}).render(promptData{TargetLang: "fr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt != "custom.tmpl: fr" {
+		t.Errorf("prompt = %q", prompt)
+	}
+}
+
+func TestLoadPromptSetErrors(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.tmpl")
+	if err := os.WriteFile(broken, []byte("{{.TargetLang"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		defaultPath string
+		rules       []PromptRule
+		want        string
+	}{
+		{name: "rule without template", rules: []PromptRule{{Languages: []string{"fr"}}}, want: "template is required"},
+		{name: "missing rule template", rules: []PromptRule{{Template: filepath.Join(dir, "missing.tmpl")}}, want: "error reading prompt template"},
+		{name: "syntax error", rules: []PromptRule{{Template: broken}}, want: "error parsing prompt template"},
+		{name: "missing default template", defaultPath: filepath.Join(dir, "missing.tmpl"), want: "error reading prompt template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadPromptSet(tt.defaultPath, tt.rules)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 분당 요청/토큰 한도 (0이면 제한 없음, 응답의 x-ratelimit-* 헤더로 알게 되면 그 한도를 따른다)
+type rateLimits struct {
+	RequestsPerMinute int   `yaml:"requestsPerMinute"`
+	TokensPerMinute   int64 `yaml:"tokensPerMinute"`
+}
+
+// 설정된 값만 덮어쓴 한도 (providers.<name>.rateLimit이 전체 rateLimit보다 우선)
+func (l rateLimits) override(other rateLimits) rateLimits {
+	if other.RequestsPerMinute > 0 {
+		l.RequestsPerMinute = other.RequestsPerMinute
+	}
+	if other.TokensPerMinute > 0 {
+		l.TokensPerMinute = other.TokensPerMinute
+	}
+	return l
+}
+
+// 분당 한도만큼 채워지는 토큰 버킷
+type tokenBucket struct {
+	limit     float64 // 분당 한도 (0이면 제한 없음)
+	available float64 // 지금 쓸 수 있는 양 (예상보다 많이 쓰면 음수가 될 수 있다)
+	updated   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.limit > 0 {
+		b.available = math.Min(b.limit, b.available+now.Sub(b.updated).Minutes()*b.limit)
+	}
+	b.updated = now
+}
+
+// n만큼 쓸 수 있을 때까지 기다려야 하는 시간 (한도보다 큰 요청은 버킷이 가득 차면 보낸다)
+func (b *tokenBucket) delay(n float64) time.Duration {
+	if b.limit <= 0 {
+		return 0
+	}
+	n = math.Min(n, b.limit)
+	if b.available >= n {
+		return 0
+	}
+	return time.Duration((n - b.available) / b.limit * float64(time.Minute))
+}
+
+func (b *tokenBucket) take(n float64) {
+	if b.limit > 0 {
+		b.available -= n
+	}
+}
+
+// 응답 헤더의 한도와 남은 양을 반영한다. 설정한 한도보다 높은 한도로는 올리지 않는다.
+func (b *tokenBucket) adapt(configured float64, limit, remaining float64, hasLimit, hasRemaining bool) {
+	if hasLimit && limit > 0 && (configured <= 0 || limit < configured) && limit != b.limit {
+		if b.limit <= 0 {
+			b.available = limit
+		}
+		b.limit = limit
+		b.available = math.Min(b.available, limit)
+	}
+	if hasRemaining && b.limit > 0 && remaining < b.available {
+		b.available = remaining
+	}
+}
+
+// 번역 백엔드 하나의 요청 속도 제한
+// 백엔드마다 하나를 만들어 모든 작업 고루틴과 같은 언어의 청크가 함께 사용한다.
+type rateLimiter struct {
+	name       string
+	configured rateLimits
+
+	mu       sync.Mutex
+	requests tokenBucket
+	tokens   tokenBucket
+	resumeAt time.Time // 429나 남은 한도 0을 받으면 이 시각까지 요청하지 않는다
+}
+
+func newRateLimiter(name string, limits rateLimits) *rateLimiter {
+	now := time.Now()
+	return &rateLimiter{
+		name:       name,
+		configured: limits,
+		requests:   tokenBucket{limit: float64(limits.RequestsPerMinute), available: float64(limits.RequestsPerMinute), updated: now},
+		tokens:     tokenBucket{limit: float64(limits.TokensPerMinute), available: float64(limits.TokensPerMinute), updated: now},
+	}
+}
+
+// 토큰 한도가 있는지 (없으면 요청 전에 토큰 수를 세지 않아도 된다)
+func (l *rateLimiter) limitsTokens() bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tokens.limit > 0
+}
+
+// 요청 하나와 예상 토큰을 쓸 수 있을 때까지 기다린 뒤 차감한다.
+func (l *rateLimiter) wait(ctx context.Context, tokens int64) error {
+	if l == nil {
+		return nil
+	}
+	logged := false
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.requests.refill(now)
+		l.tokens.refill(now)
+		delay := max(l.requests.delay(1), l.tokens.delay(float64(tokens)), l.resumeAt.Sub(now))
+		if delay <= 0 {
+			l.requests.take(1)
+			l.tokens.take(float64(tokens))
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if !logged {
+			log.Printf("Rate limit: waiting %v before the next %s request", delay.Round(time.Millisecond), l.name)
+			logged = true
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// 요청이 끝나면 예상 토큰을 실제 사용량으로 바로잡는다 (응답을 받지 못했으면 used는 0).
+func (l *rateLimiter) settle(reserved, used int64) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens.refill(time.Now())
+	l.tokens.take(float64(used - reserved))
+}
+
+// x-ratelimit-* 응답 헤더로 한도와 남은 양을 맞춘다.
+// 다른 프로세스와 같은 키를 함께 쓰면 서버의 남은 양이 더 적을 수 있으므로 적은 쪽을 따른다.
+func (l *rateLimiter) adapt(headers http.Header) {
+	if l == nil || headers == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+
+	buckets := []struct {
+		suffix     string
+		bucket     *tokenBucket
+		configured float64
+	}{
+		{"requests", &l.requests, float64(l.configured.RequestsPerMinute)},
+		{"tokens", &l.tokens, float64(l.configured.TokensPerMinute)},
+	}
+	for _, b := range buckets {
+		limit, hasLimit := headerNumber(headers, "x-ratelimit-limit-"+b.suffix)
+		remaining, hasRemaining := headerNumber(headers, "x-ratelimit-remaining-"+b.suffix)
+		b.bucket.refill(now)
+		b.bucket.adapt(b.configured, limit, remaining, hasLimit, hasRemaining)
+
+		// 한도를 모르는데 남은 양이 없으면 초기화될 때까지 모든 요청을 멈춘다
+		// (한도를 알면 버킷이 채워지는 속도대로 보낸다. reset은 한도가 모두 채워지는 시각이라 기다리기에는 너무 길다)
+		if hasRemaining && remaining <= 0 && b.bucket.limit <= 0 {
+			if reset, err := time.ParseDuration(headers.Get("x-ratelimit-reset-" + b.suffix)); err == nil {
+				l.pauseLocked(now, reset)
+			}
+		}
+	}
+}
+
+// 429 응답을 받으면 재시도하는 요청뿐 아니라 같은 백엔드의 모든 요청을 Retry-After만큼 멈춘다.
+func (l *rateLimiter) pause(d time.Duration) {
+	if l == nil || d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pauseLocked(time.Now(), d)
+}
+
+func (l *rateLimiter) pauseLocked(now time.Time, d time.Duration) {
+	if until := now.Add(d); until.After(l.resumeAt) {
+		l.resumeAt = until
+	}
+}
+
+func headerNumber(headers http.Header, name string) (float64, bool) {
+	value := headers.Get(name)
+	if value == "" {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	return number, err == nil
+}
+
+// 요청 전에 속도 제한을 기다리는 백엔드 (청크 하나를 한 번의 요청으로 보내는 채팅 백엔드)
+type rateLimitedTranslator interface {
+	// 사용할 속도 제한과 요청의 예상 토큰 수
+	rateLimit(content interface{}, sourceLang, targetLang string) (*rateLimiter, int64)
+}
+
+// 요청 컨텍스트로 차감한 예상 토큰을 전달하는 키 (응답의 실제 사용량으로 바로잡는다)
+type rateReservationKey struct{}
+
+type rateReservation struct {
+	limiter *rateLimiter
+	tokens  int64
+}
+
+// 속도 제한에 맞춰 요청 차례를 기다린다. 대기 시간은 요청 시간 제한에 포함하지 않도록 시간 제한을 걸기 전에 호출한다.
+func waitForRateLimit(ctx context.Context, translator Translator, content interface{}, sourceLang, targetLang string) (context.Context, error) {
+	limited, ok := translator.(rateLimitedTranslator)
+	if !ok {
+		return ctx, nil
+	}
+	limiter, tokens := limited.rateLimit(content, sourceLang, targetLang)
+	if limiter == nil {
+		return ctx, nil
+	}
+	if err := limiter.wait(ctx, tokens); err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, rateReservationKey{}, &rateReservation{limiter: limiter, tokens: tokens}), nil
+}
+
+// 응답의 실제 토큰 사용량으로 예약을 바로잡는다 (응답이 없으면 used는 0).
+func settleRateReservation(ctx context.Context, used int64) {
+	if reservation, ok := ctx.Value(rateReservationKey{}).(*rateReservation); ok {
+		reservation.limiter.settle(reservation.tokens, used)
+	}
+}
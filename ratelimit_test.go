@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		limit     float64
+		available float64
+		elapsed   time.Duration
+		take      float64
+		want      time.Duration
+	}{
+		{name: "unlimited", limit: 0, take: 1e9, want: 0},
+		{name: "enough available", limit: 60, available: 10, take: 10, want: 0},
+		{name: "waits for the missing part", limit: 60, available: 0, take: 1, want: time.Second},
+		{name: "refills over time", limit: 60, available: 0, elapsed: 500 * time.Millisecond, take: 1, want: 500 * time.Millisecond},
+		{name: "refill stops at the limit", limit: 60, available: 60, elapsed: time.Hour, take: 61, want: 0},
+		{name: "overspent bucket", limit: 60, available: -60, take: 1, want: 61 * time.Second},
+		{name: "request larger than the limit waits for a full bucket", limit: 60, available: 30, take: 600, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tokenBucket{limit: tt.limit, available: tt.available, updated: start}
+			b.refill(start.Add(tt.elapsed))
+			if got := b.delay(tt.take); got != tt.want {
+				t.Errorf("delay = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenBucketAdapt(t *testing.T) {
+	tests := []struct {
+		name                   string
+		limit, available       float64
+		configured             float64
+		headerLimit, remaining float64
+		hasLimit, hasRemaining bool
+		wantLimit, wantAvail   float64
+	}{
+		{name: "learns an unknown limit", headerLimit: 500, remaining: 499, hasLimit: true, hasRemaining: true, wantLimit: 500, wantAvail: 499},
+		{name: "never raises the configured limit", limit: 100, available: 100, configured: 100, headerLimit: 500, hasLimit: true, wantLimit: 100, wantAvail: 100},
+		{name: "lowers to a smaller server limit", limit: 100, available: 100, configured: 100, headerLimit: 50, hasLimit: true, wantLimit: 50, wantAvail: 50},
+		{name: "follows a smaller remaining", limit: 100, available: 80, configured: 100, remaining: 20, hasRemaining: true, wantLimit: 100, wantAvail: 20},
+		{name: "ignores a larger remaining", limit: 100, available: 10, configured: 100, remaining: 90, hasRemaining: true, wantLimit: 100, wantAvail: 10},
+		{name: "remaining without a limit is ignored", remaining: 0, hasRemaining: true, wantLimit: 0, wantAvail: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tokenBucket{limit: tt.limit, available: tt.available}
+			b.adapt(tt.configured, tt.headerLimit, tt.remaining, tt.hasLimit, tt.hasRemaining)
+			if b.limit != tt.wantLimit || b.available != tt.wantAvail {
+				t.Errorf("limit %v, available %v, want %v, %v", b.limit, b.available, tt.wantLimit, tt.wantAvail)
+			}
+		})
+	}
+}
+
+func TestRateLimiterAdaptPause(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    rateLimits
+		headers   map[string]string
+		wantPause bool
+	}{
+		{
+			name:      "unknown limit exhausted pauses until reset",
+			headers:   map[string]string{"x-ratelimit-remaining-requests": "0", "x-ratelimit-reset-requests": "2s"},
+			wantPause: true,
+		},
+		{
+			name:    "known limit is paced by the bucket instead",
+			headers: map[string]string{"x-ratelimit-limit-requests": "60", "x-ratelimit-remaining-requests": "0", "x-ratelimit-reset-requests": "1m"},
+		},
+		{
+			name:    "remaining requests",
+			headers: map[string]string{"x-ratelimit-remaining-requests": "5", "x-ratelimit-reset-requests": "2s"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter("test", tt.limits)
+			headers := make(http.Header)
+			for name, value := range tt.headers {
+				headers.Set(name, value)
+			}
+			l.adapt(headers)
+			if paused := l.resumeAt.After(time.Now()); paused != tt.wantPause {
+				t.Errorf("paused = %v, want %v (resumeAt %v)", paused, tt.wantPause, l.resumeAt)
+			}
+		})
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := newRateLimiter("test", rateLimits{RequestsPerMinute: 2, TokensPerMinute: 1000})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := l.wait(ctx, 100); err != nil {
+			t.Fatalf("request %d within the limit: %v", i+1, err)
+		}
+	}
+
+	// 한도를 다 쓰면 컨텍스트가 끝날 때까지 기다린다
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx, 100); err != context.DeadlineExceeded {
+		t.Fatalf("third request = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// 실제 사용량이 예상보다 많으면 그만큼 더 차감한다
+	l.settle(100, 900)
+	l.mu.Lock()
+	available := l.tokens.available
+	l.mu.Unlock()
+	if available > 1 { // 1000 - 예상 200 - 추가 사용 800 (+ 그동안 채워진 양)
+		t.Errorf("available tokens after settle = %v, want about 0", available)
+	}
+}
+
+func TestRateLimiterPause(t *testing.T) {
+	l := newRateLimiter("test", rateLimits{})
+	l.pause(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx, 0); err != context.DeadlineExceeded {
+		t.Fatalf("wait during a pause = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	start := time.Now()
+	if err := l.wait(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("waited %v, want until the pause ends", waited)
+	}
+
+	var nilLimiter *rateLimiter
+	if err := nilLimiter.wait(context.Background(), 1e9); err != nil {
+		t.Errorf("nil limiter: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const DEFAULT_REPORT_FILE = ".i18n/report.json"
+
+// 실행 보고서 (CI 등에서 읽을 수 있도록 실행이 끝나면 JSON으로 저장)
+// 작업별 결과와 사용량, 언어별, 파일별, 실행 전체 합계를 담는다.
+type runReport struct {
+	path string
+
+	StartedAt  time.Time               `json:"startedAt"`
+	FinishedAt time.Time               `json:"finishedAt"`
+	Succeeded  int                     `json:"succeeded"`
+	Failed     int                     `json:"failed"`
+	Unfinished int                     `json:"unfinished"` // 중단으로 취소되었거나 시작하지 못한 작업
+	Total      usageTotals             `json:"total"`
+	Languages  map[string]*usageTotals `json:"languages"`
+	Files      map[string]*usageTotals `json:"files"`
+	Jobs       []jobReport             `json:"jobs"`
+}
+
+type jobReport struct {
+	Lang    string `json:"lang"`
+	File    string `json:"file"`
+	Backend string `json:"backend"`
+	Status  string `json:"status"` // done, failed, pending (중단으로 취소됨)
+	Error   string `json:"error,omitempty"`
+	usageTotals
+}
+
+func newRunReport(path string, languages []string) *runReport {
+	r := &runReport{
+		path:      path,
+		StartedAt: time.Now(),
+		Languages: make(map[string]*usageTotals),
+		Files:     make(map[string]*usageTotals),
+	}
+	for _, lang := range languages {
+		r.Languages[lang] = &usageTotals{}
+	}
+	return r
+}
+
+func (r *runReport) addJob(job TranslationJob, backend, status string, err error, usage usageTotals) {
+	entry := jobReport{Lang: job.TargetLang, File: job.File, Backend: backend, Status: status, usageTotals: usage}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	r.Jobs = append(r.Jobs, entry)
+
+	switch status {
+	case jobDone:
+		r.Succeeded++
+	case jobFailed:
+		r.Failed++
+	default:
+		r.Unfinished++
+	}
+
+	r.Total.add(usage)
+	if r.Languages[job.TargetLang] == nil {
+		r.Languages[job.TargetLang] = &usageTotals{}
+	}
+	r.Languages[job.TargetLang].add(usage)
+	if r.Files[job.File] == nil {
+		r.Files[job.File] = &usageTotals{}
+	}
+	r.Files[job.File].add(usage)
+}
+
+func (r *runReport) languageOrder() []string {
+	return sortedKeys(r.Languages)
+}
+
+func (r *runReport) fileOrder() []string {
+	return sortedKeys(r.Files)
+}
+
+func sortedKeys(m map[string]*usageTotals) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *runReport) Save() error {
+	r.FinishedAt = time.Now()
+	sort.Slice(r.Jobs, func(i, j int) bool {
+		if r.Jobs[i].Lang != r.Jobs[j].Lang {
+			return r.Jobs[i].Lang < r.Jobs[j].Lang
+		}
+		return r.Jobs[i].File < r.Jobs[j].File
+	})
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("error creating report directory: %v", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling run report: %v", err)
+	}
+	return os.WriteFile(r.path, data, 0644)
+}
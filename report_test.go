@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestRequestStatsUsage(t *testing.T) {
+	prices := priceTable{
+		"gpt-4o": {Input: 2.5, Output: 10},
+		"fake":   {Characters: 20},
+	}
+	tests := []struct {
+		name       string
+		translator Translator
+		record     func(ctx context.Context)
+		want       usageTotals
+	}{
+		{
+			name:       "chat model priced by the longest prefix",
+			translator: &chatTranslator{name: "openai", model: "gpt-4o-2024-08-06"},
+			record: func(ctx context.Context) {
+				recordTokenUsage(ctx, openai.Usage{PromptTokens: 600, CompletionTokens: 200})
+				recordTokenUsage(ctx, openai.Usage{PromptTokens: 400, CompletionTokens: 300})
+			},
+			want: usageTotals{Requests: 2, PromptTokens: 1000, CompletionTokens: 500, Cost: 0.0075},
+		},
+		{
+			name:       "character-priced backend",
+			translator: &fakeTranslator{},
+			record:     func(ctx context.Context) { recordCharacters(ctx, []string{"Hello", "Wörld!"}) },
+			want:       usageTotals{Requests: 2, Characters: 11, Cost: 0.00022},
+		},
+		{
+			name:       "backend without a price",
+			translator: &chatTranslator{name: "local", model: "llama3"},
+			record: func(ctx context.Context) {
+				recordTokenUsage(ctx, openai.Usage{PromptTokens: 100, CompletionTokens: 50})
+			},
+			want: usageTotals{Requests: 2, PromptTokens: 100, CompletionTokens: 50, Unpriced: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &requestStats{}
+			stats.requests.Add(2)
+			tt.record(withRequestStats(context.Background(), stats))
+			got := stats.usage(tt.translator, prices)
+			if math.Abs(got.Cost-tt.want.Cost) > 1e-12 {
+				t.Errorf("cost = %v, want %v", got.Cost, tt.want.Cost)
+			}
+			got.Cost = tt.want.Cost
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report", "report.json")
+	report := newRunReport(path, []string{"fr", "de", "ko"})
+	job := func(lang, file string) TranslationJob { return TranslationJob{TargetLang: lang, File: file} }
+
+	openaiUsage := usageTotals{Requests: 3, Retries: 1, PromptTokens: 1000, CompletionTokens: 500, Cost: 0.0075}
+	deeplUsage := usageTotals{Requests: 1, MemoryHits: 2, Characters: 11, Cost: 0.00022}
+	localUsage := usageTotals{Requests: 2, PromptTokens: 100, CompletionTokens: 50, Unpriced: true}
+	report.addJob(job("fr", "common.json"), "openai", jobDone, nil, openaiUsage)
+	report.addJob(job("fr", "auth.json"), "deepl", jobDone, nil, deeplUsage)
+	report.addJob(job("de", "common.json"), "local", jobFailed, errors.New("permanent error"), localUsage)
+	report.addJob(job("ja", "common.json"), "openai", jobPending, nil, usageTotals{})
+
+	if report.Succeeded != 2 || report.Failed != 1 || report.Unfinished != 1 {
+		t.Errorf("succeeded %d, failed %d, unfinished %d", report.Succeeded, report.Failed, report.Unfinished)
+	}
+	if want := []string{"de", "fr", "ja", "ko"}; !reflect.DeepEqual(report.languageOrder(), want) {
+		t.Errorf("languages = %v, want %v", report.languageOrder(), want)
+	}
+
+	var fr usageTotals
+	fr.add(openaiUsage)
+	fr.add(deeplUsage)
+	var common usageTotals
+	common.add(openaiUsage)
+	common.add(localUsage)
+	tests := []struct {
+		name string
+		got  *usageTotals
+		want usageTotals
+	}{
+		{name: "language fr", got: report.Languages["fr"], want: fr},
+		{name: "language de", got: report.Languages["de"], want: localUsage},
+		{name: "language without jobs", got: report.Languages["ko"], want: usageTotals{}},
+		{name: "file common.json", got: report.Files["common.json"], want: common},
+		{name: "file auth.json", got: report.Files["auth.json"], want: deeplUsage},
+		{name: "total", got: &report.Total, want: usageTotals{Requests: 6, Retries: 1, MemoryHits: 2, PromptTokens: 1100, CompletionTokens: 550, Characters: 11, Cost: 0.00772, Unpriced: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := *tt.got
+			if math.Abs(got.Cost-tt.want.Cost) > 1e-12 {
+				t.Errorf("cost = %v, want %v", got.Cost, tt.want.Cost)
+			}
+			got.Cost = tt.want.Cost
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if err := report.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved map[string]interface{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for key := range saved {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if want := []string{"failed", "files", "finishedAt", "jobs", "languages", "startedAt", "succeeded", "total", "unfinished"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("report keys = %v, want %v", keys, want)
+	}
+
+	// 작업은 언어, 파일 순으로 정렬되고 사용량 필드가 작업 객체에 펼쳐진다
+	jobs := saved["jobs"].([]interface{})
+	var order []string
+	for _, entry := range jobs {
+		entry := entry.(map[string]interface{})
+		order = append(order, entry["lang"].(string)+"/"+entry["file"].(string))
+	}
+	if want := []string{"de/common.json", "fr/auth.json", "fr/common.json", "ja/common.json"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("job order = %v, want %v", order, want)
+	}
+	failed := jobs[0].(map[string]interface{})
+	if failed["backend"] != "local" || failed["status"] != jobFailed || failed["error"] != "permanent error" || failed["promptTokens"] != 100.0 || failed["unpriced"] != true {
+		t.Errorf("failed job = %v", failed)
+	}
+	if _, ok := jobs[1].(map[string]interface{})["error"]; ok {
+		t.Errorf("successful job has an error field: %v", jobs[1])
+	}
+	total := saved["total"].(map[string]interface{})
+	if total["promptTokens"] != 1100.0 || total["characters"] != 11.0 || total["unpriced"] != true {
+		t.Errorf("total = %v", total)
+	}
+	if fr := saved["languages"].(map[string]interface{})["fr"].(map[string]interface{}); fr["requests"] != 4.0 || fr["memoryHits"] != 2.0 {
+		t.Errorf("languages.fr = %v", fr)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+const (
+	MAX_RETRY_DELAY         = 60              // 지수 백오프 최대 대기 시간(초)
+	DEFAULT_REQUEST_TIMEOUT = 2 * time.Minute // 요청 하나의 기본 시간 제한
+)
+
+// 재시도 판단을 위한 오류 분류
+type errorClass int
+
+const (
+	errorTransient     errorClass = iota // 5xx, 408, 타임아웃, 네트워크 오류
+	errorRateLimit                       // 429 (Retry-After 준수)
+	errorInvalidOutput                   // 모델 응답이 올바른 JSON이 아님
+	errorPermanent                       // 잘못된 키, 없는 모델 등 재시도해도 소용없는 오류
+)
+
+func (c errorClass) String() string {
+	switch c {
+	case errorRateLimit:
+		return "rate limit"
+	case errorInvalidOutput:
+		return "invalid output"
+	case errorPermanent:
+		return "permanent"
+	default:
+		return "transient"
+	}
+}
+
+// 번역 백엔드의 HTTP 오류 (상태 코드와 Retry-After 포함)
+type providerError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *providerError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *providerError) Unwrap() error {
+	return e.Err
+}
+
+// 모델 응답을 JSON으로 해석할 수 없는 경우
+type invalidOutputError struct {
+	msg string
+}
+
+func (e *invalidOutputError) Error() string {
+	return e.msg
+}
+
+// 오류를 분류하고, 서버가 지정한 대기 시간(Retry-After)이 있으면 함께 반환한다.
+func classifyError(err error) (errorClass, time.Duration) {
+	var invalid *invalidOutputError
+	if errors.As(err, &invalid) {
+		return errorInvalidOutput, 0
+	}
+
+	// 할당량 소진은 429로 오지만 기다려도 해결되지 않는다
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == "insufficient_quota" {
+		return errorPermanent, 0
+	}
+
+	var retryAfter time.Duration
+	status := 0
+	var provErr *providerError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &provErr):
+		status, retryAfter = provErr.StatusCode, provErr.RetryAfter
+	case errors.As(err, &apiErr):
+		status = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		status = reqErr.HTTPStatusCode
+	}
+
+	switch {
+	case status == http.StatusTooManyRequests:
+		return errorRateLimit, retryAfter
+	case status == http.StatusRequestTimeout || status >= 500:
+		return errorTransient, retryAfter
+	case status >= 400:
+		return errorPermanent, 0
+	}
+
+	// 취소는 재시도하지 않는다
+	if errors.Is(err, context.Canceled) {
+		return errorPermanent, 0
+	}
+	// 타임아웃, 연결 오류, 알 수 없는 오류는 일시적인 것으로 보고 재시도
+	return errorTransient, 0
+}
+
+// go-openai 오류의 HTTP 상태 코드 (없으면 0)
+func httpStatusCode(err error) int {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatusCode
+	}
+	return 0
+}
+
+// Retry-After 헤더 파싱 (초 또는 HTTP 날짜)
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// 재시도 정책
+type retryPolicy struct {
+	MaxRetries int           // 첫 요청 이후 최대 재시도 횟수
+	BaseDelay  time.Duration // 첫 재시도 대기 시간
+	MaxDelay   time.Duration // 지수 백오프 상한
+}
+
+func (o *translateOptions) retryPolicy() retryPolicy {
+	return retryPolicy{MaxRetries: o.MaxRetries, BaseDelay: o.RetryDelay, MaxDelay: o.MaxRetryDelay}
+}
+
+// attempt번째 실패 후 대기 시간
+// 지수 백오프에 지터(50~100%)를 적용하고, 서버가 Retry-After를 지정했다면 그보다 짧게 기다리지 않는다.
+// 잘못된 응답은 서버 부하와 무관하므로 기본 대기 시간만 사용한다.
+func (p retryPolicy) backoff(attempt int, class errorClass, retryAfter time.Duration) time.Duration {
+	delay := p.BaseDelay
+	if class != errorInvalidOutput {
+		for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+			delay *= 2
+		}
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+	if delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
+
+// 요청 컨텍스트에 응답 헤더를 담아 오기 위한 키
+type responseHeadersKey struct{}
+
+// 응답 헤더를 요청 컨텍스트의 *http.Header에 기록하는 http.RoundTripper
+// go-openai 오류에는 Retry-After 등 응답 헤더가 포함되지 않으므로 전송 계층에서 가로챈다.
+type headerCapturingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *headerCapturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		if holder, ok := req.Context().Value(responseHeadersKey{}).(*http.Header); ok {
+			*holder = resp.Header.Clone()
+		}
+	}
+	return resp, err
+}
+
+func newHeaderCapturingClient() *http.Client {
+	return &http.Client{Transport: &headerCapturingTransport{base: http.DefaultTransport}}
+}
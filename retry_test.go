@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantClass      errorClass
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:           "429 with Retry-After",
+			err:            &providerError{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Second, Err: errors.New("slow down")},
+			wantClass:      errorRateLimit,
+			wantRetryAfter: 5 * time.Second,
+		},
+		{
+			name:      "openai 429",
+			err:       fmt.Errorf("request: %w", &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests, Code: "rate_limit_exceeded"}),
+			wantClass: errorRateLimit,
+		},
+		{
+			name:      "insufficient quota is permanent",
+			err:       &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests, Code: "insufficient_quota"},
+			wantClass: errorPermanent,
+		},
+		{
+			name:      "5xx is transient",
+			err:       &openai.APIError{HTTPStatusCode: http.StatusBadGateway},
+			wantClass: errorTransient,
+		},
+		{
+			name:      "5xx request error is transient",
+			err:       &openai.RequestError{HTTPStatusCode: http.StatusServiceUnavailable, Err: errors.New("unavailable")},
+			wantClass: errorTransient,
+		},
+		{
+			name:           "503 keeps the Retry-After",
+			err:            &providerError{StatusCode: http.StatusServiceUnavailable, RetryAfter: time.Second, Err: errors.New("unavailable")},
+			wantClass:      errorTransient,
+			wantRetryAfter: time.Second,
+		},
+		{
+			name:      "408 is transient",
+			err:       &providerError{StatusCode: http.StatusRequestTimeout, Err: errors.New("timeout")},
+			wantClass: errorTransient,
+		},
+		{
+			name:      "4xx is permanent",
+			err:       &openai.APIError{HTTPStatusCode: http.StatusUnauthorized},
+			wantClass: errorPermanent,
+		},
+		{
+			name:      "request timeout without a status is transient",
+			err:       fmt.Errorf("post: %w", context.DeadlineExceeded),
+			wantClass: errorTransient,
+		},
+		{
+			name:      "cancellation is not retried",
+			err:       fmt.Errorf("post: %w", context.Canceled),
+			wantClass: errorPermanent,
+		},
+		{
+			name:      "invalid model output",
+			err:       &invalidOutputError{msg: "not JSON"},
+			wantClass: errorInvalidOutput,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, retryAfter := classifyError(tt.err)
+			if class != tt.wantClass || retryAfter != tt.wantRetryAfter {
+				t.Errorf("got %s, %v, want %s, %v", class, retryAfter, tt.wantClass, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		min, max time.Duration
+	}{
+		{name: "seconds", value: "7", min: 7 * time.Second, max: 7 * time.Second},
+		{name: "HTTP date", value: time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat), min: 28 * time.Second, max: 30 * time.Second},
+		{name: "missing", value: "", min: 0, max: 0},
+		{name: "invalid", value: "soon", min: 0, max: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			if got := parseRetryAfter(header); got < tt.min || got > tt.max {
+				t.Errorf("got %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := retryPolicy{MaxRetries: 10, BaseDelay: time.Second, MaxDelay: 4 * time.Second}
+	tests := []struct {
+		name       string
+		attempt    int
+		class      errorClass
+		retryAfter time.Duration
+		min, max   time.Duration
+	}{
+		{name: "first retry", attempt: 1, class: errorTransient, min: 500 * time.Millisecond, max: time.Second},
+		{name: "doubles per attempt", attempt: 3, class: errorTransient, min: 2 * time.Second, max: 4 * time.Second},
+		{name: "capped at MaxRetryDelay", attempt: 10, class: errorTransient, min: 2 * time.Second, max: 4 * time.Second},
+		{name: "invalid output uses the base delay", attempt: 10, class: errorInvalidOutput, min: 500 * time.Millisecond, max: time.Second},
+		{name: "Retry-After beyond the cap", attempt: 1, class: errorRateLimit, retryAfter: 30 * time.Second, min: 30 * time.Second, max: 30 * time.Second},
+		{name: "shorter Retry-After", attempt: 3, class: errorRateLimit, retryAfter: time.Millisecond, min: 2 * time.Second, max: 4 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ { // 지터 범위 확인
+				if got := policy.backoff(tt.attempt, tt.class, tt.retryAfter); got < tt.min || got > tt.max {
+					t.Fatalf("got %v, want between %v and %v", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+// 정해진 오류를 차례로 돌려주는 가짜 백엔드 (오류가 떨어지면 성공)
+type failingTranslator struct {
+	fakeTranslator
+	errs   []error
+	cancel context.CancelFunc // 첫 요청에서 실행 컨텍스트를 취소
+	calls  int
+}
+
+func (f *failingTranslator) Translate(ctx context.Context, content interface{}, sourceLang, targetLang string) (interface{}, error) {
+	f.calls++
+	if f.cancel != nil {
+		f.cancel()
+		return nil, ctx.Err()
+	}
+	if f.calls <= len(f.errs) {
+		return nil, f.errs[f.calls-1]
+	}
+	return content, nil
+}
+
+func TestTranslateWithRetry(t *testing.T) {
+	transient := &openai.APIError{HTTPStatusCode: http.StatusInternalServerError}
+	tests := []struct {
+		name      string
+		errs      []error
+		cancel    bool
+		retries   int
+		wantCalls int
+		wantErr   bool
+		wantIs    error
+	}{
+		{name: "recovers from transient errors", errs: []error{transient, context.DeadlineExceeded}, retries: 2, wantCalls: 3},
+		{name: "gives up after MaxRetries", errs: []error{transient, transient, transient}, retries: 1, wantCalls: 2, wantErr: true, wantIs: transient},
+		{name: "permanent errors are not retried", errs: []error{&openai.APIError{HTTPStatusCode: http.StatusTooManyRequests, Code: "insufficient_quota"}}, retries: 3, wantCalls: 1, wantErr: true},
+		{name: "outer cancellation is not retried", cancel: true, retries: 3, wantCalls: 1, wantErr: true, wantIs: context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			translator := &failingTranslator{errs: tt.errs}
+			if tt.cancel {
+				translator.cancel = cancel
+			}
+			opts := &translateOptions{MaxRetries: tt.retries, RetryDelay: time.Millisecond, MaxRetryDelay: time.Millisecond}
+
+			_, err := translateWithRetry(ctx, translator, "x", "en", "fr", opts, &requestStats{})
+			if translator.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", translator.calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("error = %v, want %v", err, tt.wantIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const DEFAULT_STATE_FILE = ".i18n/state.json"
+
+// 작업 상태
+const (
+	jobPending = "pending" // 아직 실행하지 않았거나 중단됨
+	jobDone    = "done"
+	jobFailed  = "failed"
+)
+
+// 작업 하나(언어 × 파일)의 상태
+type jobState struct {
+	Lang       string    `json:"lang"`
+	File       string    `json:"file"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Attempts   int64     `json:"attempts"`             // 마지막 실행에서 보낸 요청 수
+	OutputHash string    `json:"outputHash,omitempty"` // 저장한 파일의 sha256
+	PromptHash string    `json:"promptHash,omitempty"` // 번역에 사용한 프롬프트(템플릿, 브랜드 보이스, 용어집)의 해시
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// 실행 상태 파일
+// 작업이 끝날 때마다 저장하므로 실패하거나 중단된 실행을 --resume으로 이어서 실행할 수 있다.
+type runState struct {
+	Source     string      `json:"source"`
+	SourceLang string      `json:"sourceLang"`
+	StartedAt  time.Time   `json:"startedAt"`
+	UpdatedAt  time.Time   `json:"updatedAt"`
+	Jobs       []*jobState `json:"jobs"`
+
+	path string
+	mu   sync.Mutex
+}
+
+func newRunState(path string, opts *translateOptions, jobs []TranslationJob) *runState {
+	state := &runState{
+		Source:     opts.Source,
+		SourceLang: opts.SourceLang,
+		StartedAt:  time.Now(),
+		path:       path,
+	}
+	for _, job := range jobs {
+		state.Jobs = append(state.Jobs, &jobState{Lang: job.TargetLang, File: job.File, Status: jobPending})
+	}
+	return state
+}
+
+func loadRunState(path string) (*runState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no previous run state at %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading run state: %v", err)
+	}
+
+	var state runState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("error parsing run state %s: %v", path, err)
+	}
+	state.path = path
+	return &state, nil
+}
+
+// 이전 실행에서 완료되지 않은(실패했거나 실행되지 않은) 작업을 현재 소스 파일로 다시 만든다.
+// 소스에서 사라진 파일의 작업은 건너뛴다.
+func (s *runState) unfinishedJobs(files []SourceFile) []TranslationJob {
+	byPath := make(map[string]SourceFile)
+	for _, file := range files {
+		byPath[file.RelPath] = file
+	}
+
+	var jobs []TranslationJob
+	for _, js := range s.Jobs {
+		if js.Status == jobDone {
+			continue
+		}
+		file, ok := byPath[js.File]
+		if !ok {
+			fmt.Printf("Skipping %s (%s): source file no longer exists\n", js.File, js.Lang)
+			continue
+		}
+		jobs = append(jobs, TranslationJob{
+			SourceLang: s.SourceLang,
+			TargetLang: js.Lang,
+			File:       js.File,
+			Content:    file.Content,
+			Context:    file.Context,
+			Document:   file.Document,
+		})
+	}
+	return jobs
+}
+
+// 작업 상태를 갱신하고 바로 파일에 기록한다.
+func (s *runState) update(job TranslationJob, status string, err error, attempts int64, outputHash, promptHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var js *jobState
+	for _, candidate := range s.Jobs {
+		if candidate.Lang == job.TargetLang && candidate.File == job.File {
+			js = candidate
+			break
+		}
+	}
+	if js == nil {
+		js = &jobState{Lang: job.TargetLang, File: job.File}
+		s.Jobs = append(s.Jobs, js)
+	}
+
+	js.Status = status
+	js.Error = ""
+	if err != nil {
+		js.Error = err.Error()
+	}
+	js.Attempts = attempts
+	js.OutputHash = outputHash
+	js.PromptHash = promptHash
+	js.UpdatedAt = time.Now()
+
+	if err := s.save(); err != nil {
+		fmt.Printf("Warning: could not save run state: %v\n", err)
+	}
+}
+
+// 상태 파일을 임시 파일에 쓴 뒤 교체한다. (중단되어도 깨진 파일이 남지 않도록)
+// 호출하는 쪽에서 s.mu를 잡고 있어야 한다.
+func (s *runState) save() error {
+	s.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *runState) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save()
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
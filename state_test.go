@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRunStateResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	opts := &translateOptions{Source: "locales/en", SourceLang: "en"}
+	var jobs []TranslationJob
+	for _, lang := range []string{"fr", "de"} {
+		for _, file := range []string{"auth.json", "admin/users.json", "old.json"} {
+			jobs = append(jobs, TranslationJob{SourceLang: "en", TargetLang: lang, File: file})
+		}
+	}
+
+	// 일부 작업만 끝난 상태로 중단된 실행
+	state := newRunState(path, opts, jobs)
+	state.update(jobs[0], jobDone, nil, 1, "hash-fr-auth", "prompt")
+	state.update(jobs[1], jobFailed, errors.New("rate limited"), 4, "", "")
+	state.update(jobs[3], jobDone, nil, 1, "hash-de-auth", "prompt")
+	state.update(jobs[4], jobPending, errors.New("canceled"), 2, "", "")
+
+	loaded, err := loadRunState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Source != "locales/en" || loaded.SourceLang != "en" || len(loaded.Jobs) != len(jobs) {
+		t.Fatalf("source %q (%s), %d jobs", loaded.Source, loaded.SourceLang, len(loaded.Jobs))
+	}
+	if js := loaded.Jobs[1]; js.Status != jobFailed || js.Error != "rate limited" || js.Attempts != 4 {
+		t.Errorf("failed job = %+v", js)
+	}
+	if js := loaded.Jobs[0]; js.OutputHash != "hash-fr-auth" || js.PromptHash != "prompt" {
+		t.Errorf("done job = %+v", js)
+	}
+
+	// 그 사이에 소스가 바뀌었다: users.json 내용이 바뀌고 old.json이 사라짐
+	files := []SourceFile{
+		{RelPath: "auth.json", Content: mustParse(t, `{"login":"Log in"}`)},
+		{RelPath: "admin/users.json", Content: mustParse(t, `{"title":"Users (changed)"}`)},
+	}
+	resumed := loaded.unfinishedJobs(files)
+	var got []string
+	for _, job := range resumed {
+		got = append(got, job.TargetLang+":"+job.File)
+		if job.SourceLang != "en" {
+			t.Errorf("%s: source language %q", job.File, job.SourceLang)
+		}
+		if job.File == "admin/users.json" && mustJSON(t, job.Content) != `{"title":"Users (changed)"}` {
+			t.Errorf("%s uses stale content %s", job.File, mustJSON(t, job.Content))
+		}
+	}
+	if want := []string{"fr:admin/users.json", "de:admin/users.json"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unfinished jobs = %v, want %v", got, want)
+	}
+
+	// 이어서 실행한 작업이 끝나면 다음 --resume에는 남은 작업만 나온다
+	loaded.update(resumed[0], jobDone, nil, 1, "hash", "prompt")
+	reloaded, err := loadRunState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, job := range reloaded.unfinishedJobs(files) {
+		got = append(got, job.TargetLang+":"+job.File)
+	}
+	if want := []string{"de:admin/users.json"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after resuming = %v, want %v", got, want)
+	}
+	if js := reloaded.Jobs[1]; js.Error != "" {
+		t.Errorf("error of the finished job was kept: %q", js.Error)
+	}
+
+	if _, err := loadRunState(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("missing state file: want an error")
+	}
+}
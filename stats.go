@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"unicode/utf8"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// 요청 통계 (작업별로 모은 뒤 언어별로 합산)
+// 청크 고루틴이 동시에 갱신하므로 atomic으로 누적한다.
+type requestStats struct {
+	requests atomic.Int64 // 백엔드에 보낸 요청 수 (재시도, 구조 복구 요청 포함)
+	retries  atomic.Int64 // 오류로 인한 재시도 횟수
+
+	memoryHits atomic.Int64 // 번역 메모리에서 재사용한 키 수
+
+	// 응답에 보고된 사용량 (실패한 요청이라도 응답을 받았으면 포함)
+	promptTokens     atomic.Int64
+	completionTokens atomic.Int64
+	characters       atomic.Int64 // DeepL/Google에 보낸 원문 글자 수
+
+	budget *budgetJob // 실행/언어별 지출 한도 (한도가 없으면 nil)
+}
+
+func (s *requestStats) Requests() int64 {
+	return s.requests.Load()
+}
+
+func (s *requestStats) Retries() int64 {
+	return s.retries.Load()
+}
+
+func (s *requestStats) MemoryHits() int64 {
+	return s.memoryHits.Load()
+}
+
+// 사용량과 가격표로 계산한 비용
+func (s *requestStats) usage(translator Translator, prices priceTable) usageTotals {
+	price, priced := priceFor(translator, prices)
+	u := usageTotals{
+		Requests:         s.Requests(),
+		Retries:          s.Retries(),
+		MemoryHits:       s.MemoryHits(),
+		PromptTokens:     s.promptTokens.Load(),
+		CompletionTokens: s.completionTokens.Load(),
+		Characters:       s.characters.Load(),
+	}
+	u.Cost = price.cost(u.PromptTokens, u.CompletionTokens, u.Characters)
+	u.Unpriced = !priced && u.Requests > 0
+	return u
+}
+
+// 요청 컨텍스트로 사용량을 전달하는 키 (Translator 인터페이스는 번역 결과만 반환하므로)
+type requestStatsKey struct{}
+
+func withRequestStats(ctx context.Context, stats *requestStats) context.Context {
+	return context.WithValue(ctx, requestStatsKey{}, stats)
+}
+
+// 채팅 응답의 토큰 사용량 기록
+func recordTokenUsage(ctx context.Context, usage openai.Usage) {
+	if stats, ok := ctx.Value(requestStatsKey{}).(*requestStats); ok {
+		stats.promptTokens.Add(int64(usage.PromptTokens))
+		stats.completionTokens.Add(int64(usage.CompletionTokens))
+		stats.budget.charge(int64(usage.PromptTokens), int64(usage.CompletionTokens), 0)
+	}
+}
+
+// 글자 수로 과금하는 백엔드에 보낸 원문 글자 수 기록
+func recordCharacters(ctx context.Context, texts []string) {
+	if stats, ok := ctx.Value(requestStatsKey{}).(*requestStats); ok {
+		var characters int64
+		for _, text := range texts {
+			characters += int64(utf8.RuneCountInString(text))
+		}
+		stats.characters.Add(characters)
+		stats.budget.charge(0, 0, characters)
+	}
+}
+
+// 누적 사용량과 비용 (작업, 언어, 파일, 실행 단위)
+type usageTotals struct {
+	Requests         int64   `json:"requests"`
+	Retries          int64   `json:"retries"`
+	MemoryHits       int64   `json:"memoryHits"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	Characters       int64   `json:"characters,omitempty"`
+	Cost             float64 `json:"cost"`               // USD, 가격표에 있는 백엔드만
+	Unpriced         bool    `json:"unpriced,omitempty"` // 가격표에 없는 백엔드의 요청이 있음
+}
+
+func (u *usageTotals) add(other usageTotals) {
+	u.Requests += other.Requests
+	u.Retries += other.Retries
+	u.MemoryHits += other.MemoryHits
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.Characters += other.Characters
+	u.Cost += other.Cost
+	u.Unpriced = u.Unpriced || other.Unpriced
+}
+
+// "1200 prompt + 300 completion tokens, $0.0060" 형식
+func (u usageTotals) String() string {
+	text := fmt.Sprintf("%d prompt + %d completion tokens", u.PromptTokens, u.CompletionTokens)
+	if u.Characters > 0 {
+		text += fmt.Sprintf(", %d characters", u.Characters)
+	}
+	switch {
+	case u.Unpriced && u.Cost == 0:
+		text += ", cost unknown"
+	case u.Unpriced:
+		text += fmt.Sprintf(", $%.4f + unpriced", u.Cost)
+	default:
+		text += fmt.Sprintf(", $%.4f", u.Cost)
+	}
+	return text
+}
+
+// 언어별, 파일별 요청 통계와 사용량 출력
+func printAttemptSummary(w io.Writer, report *runReport) {
+	fmt.Fprintln(w, "\nRequests per language:")
+	for _, lang := range report.languageOrder() {
+		u := report.Languages[lang]
+		fmt.Fprintf(w, "- %s (%s): %d requests, %d retries, %d keys from translation memory, %s\n", languageMap[lang], lang, u.Requests, u.Retries, u.MemoryHits, u)
+	}
+
+	fmt.Fprintln(w, "\nUsage per file:")
+	for _, file := range report.fileOrder() {
+		u := report.Files[file]
+		fmt.Fprintf(w, "- %s: %d requests, %s\n", file, u.Requests, u)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	tiktoken "github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// 채팅 요청 하나에 메시지 내용 외에 붙는 토큰 (메시지 구분자, role, 응답 시작 토큰)
+const CHAT_REQUEST_OVERHEAD_TOKENS = 7
+
+// 모델에 맞는 토크나이저로 토큰 수를 센다.
+// 인코딩은 가격표의 encoding, tiktoken의 모델 표 순으로 정하며, 둘 다 없으면 estimateTokens 근사치를 사용한다.
+type tokenCounter struct {
+	encoding *tiktoken.Tiktoken // nil이면 근사치
+	Encoding string             // 인코딩 이름 (근사치면 빈 문자열)
+}
+
+// 인코딩 파일은 내려받지 않고 바이너리에 포함된 것을 사용한다 (네트워크 없이 정확한 토큰 수)
+func init() {
+	tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+}
+
+var (
+	tokenCountersMu sync.Mutex
+	tokenCounters   = make(map[string]*tokenCounter) // 모델 → 토크나이저 (인코딩 파일은 한 번만 읽는다)
+)
+
+func tokenCounterFor(model, encoding string) *tokenCounter {
+	tokenCountersMu.Lock()
+	defer tokenCountersMu.Unlock()
+
+	key := model + "\x00" + encoding
+	if counter, ok := tokenCounters[key]; ok {
+		return counter
+	}
+
+	counter := &tokenCounter{}
+	var enc *tiktoken.Tiktoken
+	var err error
+	if encoding != "" {
+		enc, err = tiktoken.GetEncoding(encoding)
+	} else {
+		enc, err = tiktoken.EncodingForModel(model)
+	}
+	if err == nil {
+		counter.encoding = enc
+		counter.Encoding = encoding
+		if encoding == "" {
+			counter.Encoding = tiktoken.MODEL_TO_ENCODING[model]
+			for prefix, name := range tiktoken.MODEL_PREFIX_TO_ENCODING {
+				if counter.Encoding == "" && strings.HasPrefix(model, prefix) {
+					counter.Encoding = name
+				}
+			}
+		}
+	} else {
+		log.Printf("Warning: no tokenizer for model %q (%v), token counts are approximate", model, err)
+	}
+	tokenCounters[key] = counter
+	return counter
+}
+
+func (c *tokenCounter) count(text string) int {
+	if c.encoding == nil {
+		return estimateTokens(text)
+	}
+	return len(c.encoding.Encode(text, nil, nil))
+}
+
+func (c *tokenCounter) approximate() bool {
+	return c.encoding == nil
+}
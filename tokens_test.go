@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestTokenCounterFor(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        string
+		encoding     string
+		wantEncoding string // 비어 있으면 근사치
+		text         string
+		want         int
+	}{
+		{name: "model table", model: "gpt-4o", wantEncoding: "o200k_base", text: "hello world", want: 2},
+		{name: "model prefix", model: "gpt-4o-2024-08-06", wantEncoding: "o200k_base", text: "hello world", want: 2},
+		{name: "older model", model: "gpt-4", wantEncoding: "cl100k_base", text: "hello world", want: 2},
+		{name: "encoding from pricing", model: "my-deployment", encoding: "cl100k_base", wantEncoding: "cl100k_base", text: "hello world", want: 2},
+		{name: "unknown model", model: "llama3", text: "hello world", want: estimateTokens("hello world")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := tokenCounterFor(tt.model, tt.encoding)
+			if counter.Encoding != tt.wantEncoding || counter.approximate() != (tt.wantEncoding == "") {
+				t.Errorf("encoding %q (approximate %v), want %q", counter.Encoding, counter.approximate(), tt.wantEncoding)
+			}
+			if got := counter.count(tt.text); got != tt.want {
+				t.Errorf("count(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
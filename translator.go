@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// 번역 백엔드
+// content는 *orderedMap 트리이며, 같은 구조의 번역된 트리를 반환해야 한다.
+type Translator interface {
+	Name() string
+	// 번역 결과에 영향을 주는 프롬프트의 해시 (번역 메모리 키에 사용, 프롬프트가 없는 백엔드는 빈 문자열)
+	PromptHash() string
+	// 작업에 선택된 프롬프트 템플릿, 키별 맥락을 사용하는 백엔드 (프롬프트가 없는 백엔드는 자기 자신)
+	WithPrompt(prompt *promptTemplate) Translator
+	WithContext(notes contextNotes) Translator
+	Translate(ctx context.Context, content interface{}, sourceLang, targetLang string) (interface{}, error)
+}
+
+const DEFAULT_PROVIDER = "openai"
+
+// 지원하는 번역 백엔드 이름
+var providerNames = []string{"openai", "azure", "local", "deepl", "google"}
+
+// 이름으로 번역 백엔드를 생성한다.
+// 인증 정보와 엔드포인트는 환경 변수를 우선하고, 없으면 설정 파일의 providers 항목을 사용한다.
+// --dry-run은 요청을 보내지 않으므로 인증 정보가 없어도 된다.
+// 모델은 --model(provider.model), providers.<name>.model, 백엔드 기본값 순으로 정한다.
+func newTranslator(name string, opts *translateOptions) (Translator, error) {
+	config := opts.Providers[name]
+	model := opts.Model
+	if model == "" {
+		model = config.Model
+	}
+	limits := opts.RateLimit.override(config.RateLimit)
+	chat := func(client *openai.Client, model string) *chatTranslator {
+		price, _ := opts.Pricing.lookup(name+":"+model, model)
+		return &chatTranslator{
+			name:        name,
+			client:      client,
+			model:       model,
+			temperature: opts.Temperature,
+			brandVoice:  opts.BrandVoice,
+			glossary:    opts.glossary,
+			prompt:      opts.prompts.defaultTemplate,
+			limiter:     newRateLimiter(name+":"+model, limits),
+			encoding:    price.Encoding,
+		}
+	}
+
+	switch name {
+	case "openai":
+		apiKey := envOr("OPENAI_API_KEY", config.APIKey)
+		if apiKey == "" && !opts.DryRun {
+			return nil, fmt.Errorf("OPENAI_API_KEY is not set (environment, .env file or config)")
+		}
+		if model == "" {
+			model = openai.GPT4o
+		}
+		clientConfig := openai.DefaultConfig(apiKey)
+		if config.BaseURL != "" {
+			clientConfig.BaseURL = config.BaseURL
+		}
+		clientConfig.HTTPClient = newHeaderCapturingClient()
+		return chat(openai.NewClientWithConfig(clientConfig), model), nil
+
+	case "azure":
+		apiKey := envOr("AZURE_OPENAI_API_KEY", config.APIKey)
+		endpoint := envOr("AZURE_OPENAI_ENDPOINT", config.BaseURL)
+		if (apiKey == "" || endpoint == "") && !opts.DryRun {
+			return nil, fmt.Errorf("AZURE_OPENAI_API_KEY and AZURE_OPENAI_ENDPOINT must be set for the azure provider")
+		}
+		if model == "" {
+			model = os.Getenv("AZURE_OPENAI_DEPLOYMENT")
+		}
+		if model == "" {
+			return nil, fmt.Errorf("set AZURE_OPENAI_DEPLOYMENT or --model for the azure provider")
+		}
+		clientConfig := openai.DefaultAzureConfig(apiKey, endpoint)
+		if version := envOr("AZURE_OPENAI_API_VERSION", config.APIVersion); version != "" {
+			clientConfig.APIVersion = version
+		}
+		// 모델 이름을 배포 이름으로 그대로 사용
+		clientConfig.AzureModelMapperFunc = func(model string) string { return model }
+		clientConfig.HTTPClient = newHeaderCapturingClient()
+		return chat(openai.NewClientWithConfig(clientConfig), model), nil
+
+	case "local":
+		// Ollama, vLLM 등 OpenAI 호환 엔드포인트 (예: http://localhost:11434/v1)
+		baseURL := envOr("LOCAL_LLM_BASE_URL", config.BaseURL)
+		if baseURL == "" && !opts.DryRun {
+			return nil, fmt.Errorf("LOCAL_LLM_BASE_URL is not set for the local provider")
+		}
+		if model == "" {
+			model = os.Getenv("LOCAL_LLM_MODEL")
+		}
+		if model == "" {
+			return nil, fmt.Errorf("set LOCAL_LLM_MODEL or --model for the local provider")
+		}
+		clientConfig := openai.DefaultConfig(envOr("LOCAL_LLM_API_KEY", config.APIKey))
+		clientConfig.BaseURL = baseURL
+		clientConfig.HTTPClient = newHeaderCapturingClient()
+		return chat(openai.NewClientWithConfig(clientConfig), model), nil
+
+	case "deepl":
+		apiKey := envOr("DEEPL_API_KEY", config.APIKey)
+		if apiKey == "" && !opts.DryRun {
+			return nil, fmt.Errorf("DEEPL_API_KEY is not set for the deepl provider")
+		}
+		return newDeepLTranslator(apiKey, envOr("DEEPL_API_URL", config.BaseURL), newRateLimiter(name, limits)), nil
+
+	case "google":
+		apiKey := envOr("GOOGLE_TRANSLATE_API_KEY", config.APIKey)
+		if apiKey == "" && !opts.DryRun {
+			return nil, fmt.Errorf("GOOGLE_TRANSLATE_API_KEY is not set for the google provider")
+		}
+		return newGoogleTranslator(apiKey, envOr("GOOGLE_TRANSLATE_API_URL", config.BaseURL), newRateLimiter(name, limits)), nil
+	}
+	return nil, fmt.Errorf("unknown provider %q (available: %s)", name, strings.Join(providerNames, ", "))
+}
+
+// 언어별로 사용할 번역 백엔드
+type translatorSet struct {
+	defaultTranslator Translator
+	byLanguage        map[string]Translator
+}
+
+// 실행 기본 백엔드와 언어별 지정(lang → provider)으로 필요한 백엔드만 생성한다.
+func newTranslatorSet(opts *translateOptions) (*translatorSet, error) {
+	created := make(map[string]Translator)
+	get := func(name string) (Translator, error) {
+		if translator, ok := created[name]; ok {
+			return translator, nil
+		}
+		translator, err := newTranslator(name, opts)
+		if err != nil {
+			return nil, err
+		}
+		created[name] = translator
+		return translator, nil
+	}
+
+	defaultTranslator, err := get(opts.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &translatorSet{defaultTranslator: defaultTranslator, byLanguage: make(map[string]Translator)}
+
+	var langs []string
+	for lang := range opts.ProviderFor {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		translator, err := get(opts.ProviderFor[lang])
+		if err != nil {
+			return nil, fmt.Errorf("provider for %s: %v", lang, err)
+		}
+		set.byLanguage[lang] = translator
+	}
+	return set, nil
+}
+
+func (s *translatorSet) forLanguage(lang string) Translator {
+	if translator, ok := s.byLanguage[lang]; ok {
+		return translator
+	}
+	return s.defaultTranslator
+}
+
+// OpenAI Chat Completions API 기반 백엔드 (OpenAI, Azure OpenAI, OpenAI 호환 로컬 엔드포인트)
+type chatTranslator struct {
+	name        string
+	client      *openai.Client
+	model       string
+	temperature float32
+	brandVoice  string
+	glossary    *glossary
+	prompt      *promptTemplate
+	notes       contextNotes
+	limiter     *rateLimiter // 백엔드의 모든 복사본이 함께 사용하는 분당 요청/토큰 한도
+	encoding    string       // 속도 제한에 쓸 토크나이저 인코딩 (pricing의 encoding)
+}
+
+func (t *chatTranslator) Name() string {
+	return t.name + ":" + t.model
+}
+
+func (t *chatTranslator) PromptHash() string {
+	return hashBytes([]byte(t.prompt.hash + "\x00" + strings.TrimSpace(t.brandVoice) + "\x00" + t.glossary.Hash()))
+}
+
+func (t *chatTranslator) WithPrompt(prompt *promptTemplate) Translator {
+	copied := *t
+	copied.prompt = prompt
+	return &copied
+}
+
+func (t *chatTranslator) WithContext(notes contextNotes) Translator {
+	copied := *t
+	copied.notes = notes
+	return &copied
+}
+
+func (t *chatTranslator) Translate(ctx context.Context, content interface{}, sourceLang, targetLang string) (interface{}, error) {
+	return t.translateContent(ctx, content, sourceLang, targetLang)
+}
+
+// 분당 토큰 한도가 있으면 프롬프트와 예상 출력(요청 JSON과 같은 길이)의 토큰 수를 센다.
+func (t *chatTranslator) rateLimit(content interface{}, sourceLang, targetLang string) (*rateLimiter, int64) {
+	if !t.limiter.limitsTokens() {
+		return t.limiter, 0
+	}
+	prompt, err := t.buildPrompt(content, sourceLang, targetLang)
+	if err != nil {
+		return t.limiter, 0 // 요청에서 같은 오류가 난다
+	}
+	payload, _ := json.Marshal(content)
+	counter := tokenCounterFor(t.model, t.encoding)
+	return t.limiter, int64(counter.count(prompt) + CHAT_REQUEST_OVERHEAD_TOKENS + counter.count(string(payload)))
+}
+
+// 기계 번역 API는 문자열 단위로 번역하므로 플레이스홀더가 번역되지 않도록 translate="no" 요소로 감싼다.
+var protectedPattern = regexp.MustCompile(`<span translate="no">(.*?)</span>`)
+
+func protectPlaceholders(text string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return `<span translate="no">` + match + `</span>`
+	})
+}
+
+func restorePlaceholders(text string) string {
+	return protectedPattern.ReplaceAllString(text, "$1")
+}
+
+// 문자열 배열 번역 API를 트리 번역으로 감싼다.
+// 문자열 리프를 순서대로 모아 batchSize 단위로 번역한 뒤 같은 구조로 다시 조립한다.
+// 이런 백엔드는 원문 글자 수로 과금하므로 번역된 배치의 글자 수를 사용량으로 기록한다.
+func translateLeaves(ctx context.Context, content interface{}, batchSize int, translate func(texts []string) ([]string, error)) (interface{}, error) {
+	if content == nil {
+		return nil, fmt.Errorf("입력 데이터가 비어있습니다")
+	}
+
+	var paths []string
+	var texts, originals []string
+	walkLeaves(content, "", func(path string, value interface{}) {
+		if text, ok := value.(string); ok {
+			paths = append(paths, path)
+			texts = append(texts, protectPlaceholders(text))
+			originals = append(originals, text)
+		}
+	})
+
+	translated := make(map[string]interface{}, len(paths))
+	for start := 0; start < len(texts); start += batchSize {
+		end := start + batchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		results, err := translate(texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		recordCharacters(ctx, originals[start:end])
+		if len(results) != end-start {
+			return nil, fmt.Errorf("expected %d translations, got %d", end-start, len(results))
+		}
+		for i, result := range results {
+			translated[paths[start+i]] = restorePlaceholders(result)
+		}
+	}
+
+	sourceLeaves := flattenTree(content)
+	result, _ := rebuildTree(content, "", func(path string) (interface{}, bool) {
+		if value, ok := translated[path]; ok {
+			return value, true
+		}
+		return sourceLeaves[path], true
+	})
+	return result, nil
+}
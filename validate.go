@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const DEFAULT_REPAIR_ATTEMPTS = 2 // 구조가 깨진 키만 다시 요청하는 최대 횟수
+
+// 번역 결과의 구조 문제 (JSON Pointer 경로 기준)
+type structureIssue struct {
+	Path     string
+	Kind     string // "missing", "extra", "type"
+	Expected string // 소스의 JSON 타입
+	Actual   string // 번역 결과의 JSON 타입
+}
+
+func (i structureIssue) String() string {
+	switch i.Kind {
+	case "missing":
+		return fmt.Sprintf("missing %s", i.Path)
+	case "extra":
+		return fmt.Sprintf("extra %s", i.Path)
+	default:
+		return fmt.Sprintf("type %s (expected %s, got %s)", i.Path, i.Expected, i.Actual)
+	}
+}
+
+// 구조 문제 목록을 하나의 오류로 변환 (너무 길어지지 않도록 앞부분만 나열)
+func structureError(issues []structureIssue) error {
+	const maxListed = 10
+
+	var parts []string
+	for i, issue := range issues {
+		if i == maxListed {
+			parts = append(parts, fmt.Sprintf("and %d more", len(issues)-maxListed))
+			break
+		}
+		parts = append(parts, issue.String())
+	}
+	return fmt.Errorf("translated JSON does not match source structure: %s", strings.Join(parts, "; "))
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case *orderedMap:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// 소스와 번역 트리를 함께 순회하며 누락/추가/타입 변경된 키를 찾는다.
+func validateStructure(source, translated interface{}) []structureIssue {
+	var issues []structureIssue
+	compareStructure(source, translated, "", &issues)
+	return issues
+}
+
+func compareStructure(source, translated interface{}, path string, issues *[]structureIssue) {
+	if jsonType(source) != jsonType(translated) {
+		*issues = append(*issues, structureIssue{Path: path, Kind: "type", Expected: jsonType(source), Actual: jsonType(translated)})
+		return
+	}
+
+	switch s := source.(type) {
+	case *orderedMap:
+		t := translated.(*orderedMap)
+		for _, key := range s.Keys() {
+			childPath := joinPointer(path, key)
+			sourceChild, _ := s.Get(key)
+			translatedChild, ok := t.Get(key)
+			if !ok {
+				*issues = append(*issues, structureIssue{Path: childPath, Kind: "missing", Expected: jsonType(sourceChild)})
+				continue
+			}
+			compareStructure(sourceChild, translatedChild, childPath, issues)
+		}
+		for _, key := range t.Keys() {
+			if _, ok := s.Get(key); !ok {
+				translatedChild, _ := t.Get(key)
+				*issues = append(*issues, structureIssue{Path: joinPointer(path, key), Kind: "extra", Actual: jsonType(translatedChild)})
+			}
+		}
+	case []interface{}:
+		t := translated.([]interface{})
+		for i, sourceChild := range s {
+			childPath := joinPointer(path, strconv.Itoa(i))
+			if i >= len(t) {
+				*issues = append(*issues, structureIssue{Path: childPath, Kind: "missing", Expected: jsonType(sourceChild)})
+				continue
+			}
+			compareStructure(sourceChild, t[i], childPath, issues)
+		}
+		for i := len(s); i < len(t); i++ {
+			*issues = append(*issues, structureIssue{Path: joinPointer(path, strconv.Itoa(i)), Kind: "extra", Actual: jsonType(t[i])})
+		}
+	}
+}
+
+// 번역 결과에서 소스와 같은 경로·같은 타입인 리프만 골라낸다.
+// 추가된 키는 버리고, 누락되거나 타입이 바뀐 리프는 결과에 포함되지 않는다.
+func acceptedLeaves(sourceLeaves map[string]interface{}, translated interface{}) map[string]interface{} {
+	accepted := make(map[string]interface{})
+	for path, value := range flattenTree(translated) {
+		sourceValue, ok := sourceLeaves[path]
+		if ok && jsonType(sourceValue) == jsonType(value) {
+			accepted[path] = value
+		}
+	}
+	return accepted
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateStructure(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		translated string
+		want       []string
+	}{
+		{name: "same structure", source: `{"a":"A","n":{"b":"B"},"l":["x",1]}`, translated: `{"a":"a","n":{"b":"b"},"l":["y",1]}`},
+		{name: "missing key", source: `{"a":"A","n":{"b":"B","c":"C"}}`, translated: `{"a":"a","n":{"b":"b"}}`, want: []string{"missing /n/c"}},
+		{name: "extra key", source: `{"a":"A"}`, translated: `{"a":"a","z":"z"}`, want: []string{"extra /z"}},
+		{name: "changed type", source: `{"a":"A","n":{"b":"B"}}`, translated: `{"a":["a"],"n":"b"}`, want: []string{"type /a (expected string, got array)", "type /n (expected object, got string)"}},
+		{name: "shorter array", source: `{"l":["x","y"]}`, translated: `{"l":["x"]}`, want: []string{"missing /l/1"}},
+		{name: "longer array", source: `{"l":["x"]}`, translated: `{"l":["x","y"]}`, want: []string{"extra /l/1"}},
+		{name: "escaped key", source: `{"a/b":"A","c~d":"C"}`, translated: `{}`, want: []string{"missing /a~1b", "missing /c~0d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range validateStructure(mustParse(t, tt.source), mustParse(t, tt.translated)) {
+				got = append(got, issue.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 준비된 응답을 차례로 돌려주는 가짜 백엔드 (응답이 떨어지면 마지막 응답을 반복)
+type scriptedTranslator struct {
+	fakeTranslator
+	responses []string
+}
+
+func (s *scriptedTranslator) Translate(ctx context.Context, content interface{}, sourceLang, targetLang string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	request, _ := json.Marshal(content)
+	s.requests = append(s.requests, string(request))
+	response := s.responses[min(len(s.requests), len(s.responses))-1]
+	return parseOrderedJSON([]byte(response))
+}
+
+func (s *scriptedTranslator) WithContext(contextNotes) Translator { return s }
+
+func TestTranslateChunkRepair(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		responses    []string
+		attempts     int
+		wantRequests []string
+		want         string
+		wantErr      string
+	}{
+		{
+			name:         "missing keys, then an extra key, then valid output",
+			source:       `{"a":"A","b":"B","c":"C"}`,
+			responses:    []string{`{"a":"a"}`, `{"b":"b","x":"extra"}`, `{"c":"c"}`},
+			attempts:     2,
+			wantRequests: []string{`{"a":"A","b":"B","c":"C"}`, `{"b":"B","c":"C"}`, `{"c":"C"}`},
+			want:         `{"a":"a","b":"b","c":"c"}`,
+		},
+		{
+			name:         "extra keys alone are dropped without a repair request",
+			source:       `{"a":"A"}`,
+			responses:    []string{`{"a":"a","x":"extra"}`},
+			attempts:     2,
+			wantRequests: []string{`{"a":"A"}`},
+			want:         `{"a":"a"}`,
+		},
+		{
+			name:         "missing array element is re-requested at its index",
+			source:       `{"l":["x","y","z"]}`,
+			responses:    []string{`{"l":["X"]}`, `{"l":{"1":"Y","2":"Z"}}`},
+			attempts:     1,
+			wantRequests: []string{`{"l":["x","y","z"]}`, `{"l":{"1":"y","2":"z"}}`},
+			want:         `{"l":["X","Y","Z"]}`,
+		},
+		{
+			name:         "still missing after the repair attempts",
+			source:       `{"a":"A","b":"B"}`,
+			responses:    []string{`{"a":"a"}`, `{"x":"extra"}`},
+			attempts:     2,
+			wantRequests: []string{`{"a":"A","b":"B"}`, `{"b":"B"}`, `{"b":"B"}`},
+			wantErr:      "translated JSON does not match source structure: missing /b; extra /x",
+		},
+		{
+			name:         "no repair attempts",
+			source:       `{"a":"A","b":"B"}`,
+			responses:    []string{`{"a":"a"}`},
+			attempts:     0,
+			wantRequests: []string{`{"a":"A","b":"B"}`},
+			wantErr:      "missing /b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translator := &scriptedTranslator{responses: tt.responses}
+			opts := testTranslateOptions(1000)
+			opts.RepairAttempts = tt.attempts
+			source := mustParse(t, tt.source)
+
+			leaves, _, err := translateChunk(context.Background(), translator, source, "en", "fr", nil, opts, &requestStats{})
+			if !reflect.DeepEqual(translator.requests, tt.wantRequests) {
+				t.Errorf("requests = %v, want %v", translator.requests, tt.wantRequests)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, _ := rebuildTree(source, "", func(path string) (interface{}, bool) {
+				value, ok := leaves[path]
+				return value, ok
+			})
+			if got := mustJSON(t, result); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 번역가 검토용 XLIFF 2.0 교환
+// 언어마다 <lang>.xlf 하나를 만들고 소스 파일마다 <file>, 문자열 키마다 <unit>을 둔다.
+// id는 NMTOKEN이어야 하므로 파일 경로는 original, 키 경로는 unit의 name 속성에 담는다.
+// 대상 값은 translate로 만든 기계 번역이며, segment의 상태(state)로 검토가 필요한 값을 표시한다.
+
+// segment 상태
+const (
+	xliffStateInitial    = "initial"    // 번역 없음
+	xliffStateTranslated = "translated" // 기계 번역 (검토 필요 여부는 subState로 구분)
+	xliffStateReviewed   = "reviewed"   // 검토됨 (가져올 때 승인으로 기록)
+	xliffStateFinal      = "final"      // 승인됨
+)
+
+// 플레이스홀더 등 문제가 있거나 소스가 바뀌어 검토가 필요한 번역 (state="translated"와 함께 쓴다)
+const xliffSubStateNeedsReview = "go-multilingual:needs-review"
+
+type xliffDocument struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string      `xml:"version,attr"`
+	SrcLang string      `xml:"srcLang,attr"`
+	TrgLang string      `xml:"trgLang,attr,omitempty"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	ID       string      `xml:"id,attr"`
+	Original string      `xml:"original,attr"`
+	Units    []xliffUnit `xml:"unit"`
+}
+
+type xliffUnit struct {
+	ID      string       `xml:"id,attr"`
+	Name    string       `xml:"name,attr"` // 키 경로
+	Notes   *xliffNotes  `xml:"notes"`     // 2.0에서는 빈 <notes>를 허용하지 않는다
+	Segment xliffSegment `xml:"segment"`
+}
+
+type xliffNotes struct {
+	Notes []xliffNote `xml:"note"`
+}
+
+type xliffSegment struct {
+	State    string       `xml:"state,attr,omitempty"`
+	SubState string       `xml:"subState,attr,omitempty"`
+	Source   string       `xml:"source"`
+	Target   *xliffTarget `xml:"target"`
+}
+
+// <target>의 내용 (인라인 요소를 알아볼 수 있도록 XML 원문 그대로 읽고 쓴다)
+type xliffTarget struct {
+	Inner string `xml:",innerxml"`
+}
+
+func newXLIFFTarget(text string) *xliffTarget {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return &xliffTarget{Inner: b.String()}
+}
+
+// 대상 텍스트. 내보낸 값은 일반 텍스트이므로, CAT 도구가 넣은 <ph>, <pc>, <mrk> 같은 인라인 요소는
+// 로케일 값으로 되돌릴 수 없어 오류로 처리한다.
+func (t *xliffTarget) text() (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(t.Inner))
+	var b strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid <target>: %v", err)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			return "", fmt.Errorf("<target> contains the inline element <%s>, which cannot be written to a locale file; replace it with plain text", token.Name.Local)
+		case xml.CharData:
+			b.Write(token)
+		}
+	}
+}
+
+type xliffNote struct {
+	Category string `xml:"category,attr,omitempty"`
+	Text     string `xml:",chardata"`
+}
+
+func (u *xliffUnit) addNote(category, text string) {
+	if u.Notes == nil {
+		u.Notes = &xliffNotes{}
+	}
+	u.Notes.Notes = append(u.Notes.Notes, xliffNote{Category: category, Text: text})
+}
+
+// 검토가 필요한 번역인지
+func (s *xliffSegment) needsReview() bool {
+	return s.State == xliffStateTranslated && s.SubState == xliffSubStateNeedsReview
+}
+
+// 가져올 수 있는(검토가 끝난) 상태인지, 승인된 상태인지
+// state가 없으면 기본값 initial이지만, 대상 값을 직접 채운 파일도 받을 수 있도록 translated로 취급한다.
+// translated는 기계 번역과 구별할 수 없으므로 승인하지 않는다. (--incremental 없는 translate 실행이 덮어쓸 수 있다)
+func (u *xliffUnit) importable() (ok, approved bool) {
+	s := &u.Segment
+	if s.Target == nil || s.Target.Inner == "" || s.State == xliffStateInitial || s.needsReview() {
+		return false, false
+	}
+	approved = s.State == xliffStateReviewed || s.State == xliffStateFinal
+	return approved || s.State == xliffStateTranslated || s.State == "", approved
+}
+
+// 언어별 XLIFF 파일 경로
+func xliffPath(dir, lang string) string {
+	return filepath.Join(dir, lang+".xlf")
+}
+
+// 소스 파일과 기존 번역으로 언어별 XLIFF를 만든다.
+func exportXLIFF(opts *translateOptions, dir string) error {
+	sourceFiles, err := discoverSourceFiles(opts.Source, opts.SourceLang, opts.Include, opts.ExcludeFiles)
+	if err != nil {
+		return err
+	}
+	languages := resolveTargetLanguages(opts)
+	if len(languages) == 0 {
+		return fmt.Errorf("no target languages: use --to, --all-from-languageMap or targets in the config file")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating directory: %v", err)
+	}
+
+	for _, lang := range languages {
+		doc := xliffDocument{Version: "2.0", SrcLang: opts.SourceLang, TrgLang: lang}
+		counts := make(map[string]int)
+		for _, file := range sourceFiles {
+			job := prepareJob(TranslationJob{
+				SourceLang: opts.SourceLang,
+				TargetLang: lang,
+				File:       file.RelPath,
+				Content:    file.Content,
+				Context:    file.Context,
+				Document:   file.Document,
+			}, opts)
+
+			existing, err := readLocaleTree(targetFilePath(opts.OutDir, lang, file.RelPath), lang)
+			if err != nil {
+				return fmt.Errorf("error reading existing translation: %v", err)
+			}
+			snapshot, err := loadSnapshot(snapshotPath(opts.SnapshotDir, lang, file.RelPath))
+			if err != nil {
+				return err
+			}
+
+			units := xliffUnits(job, flattenTree(existing), snapshot, opts)
+			for _, unit := range units {
+				if unit.Segment.needsReview() {
+					counts[xliffSubStateNeedsReview]++
+				} else {
+					counts[unit.Segment.State]++
+				}
+			}
+			doc.Files = append(doc.Files, xliffFile{
+				ID:       fmt.Sprintf("f%d", len(doc.Files)+1),
+				Original: file.RelPath,
+				Units:    units,
+			})
+		}
+
+		data, err := xml.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling XLIFF: %v", err)
+		}
+		path := xliffPath(dir, lang)
+		if err := os.WriteFile(path, append([]byte(xml.Header), append(data, '\n')...), 0644); err != nil {
+			return fmt.Errorf("error writing %s: %v", path, err)
+		}
+		fmt.Printf("%s: %d translated, %d needs review, %d new, %d approved → %s\n", lang,
+			counts[xliffStateTranslated], counts[xliffSubStateNeedsReview], counts[xliffStateInitial], counts[xliffStateFinal], path)
+	}
+	return nil
+}
+
+// 작업의 문자열 키마다 unit을 만든다.
+func xliffUnits(job TranslationJob, existing, snapshot map[string]interface{}, opts *translateOptions) []xliffUnit {
+	approved := opts.approvals.forFile(job.TargetLang, job.File)
+
+	var units []xliffUnit
+	walkLeaves(job.Content, "", func(path string, value interface{}) {
+		source, ok := value.(string)
+		if !ok {
+			return
+		}
+		unit := xliffUnit{ID: fmt.Sprintf("u%d", len(units)+1), Name: path, Segment: xliffSegment{State: xliffStateInitial, Source: source}}
+		if note := job.Context[path].note(); note != "" {
+			unit.addNote("developer", note)
+		}
+
+		translated, ok := existing[path].(string)
+		if !ok {
+			units = append(units, unit)
+			return
+		}
+		unit.Segment.Target = newXLIFFTarget(translated)
+
+		var problems []string
+		if entry, ok := approved[path]; ok {
+			if entry.Source == source {
+				unit.Segment.State = xliffStateFinal
+				units = append(units, unit)
+				return
+			}
+			problems = append(problems, "source changed since the translation was approved")
+		} else if previous, ok := snapshot[path]; ok && previous != source {
+			problems = append(problems, "source changed since the last translation")
+		}
+		if issue := checkTranslatedValue(path, source, translated, job.TargetLang, opts.glossary, job.Context[path].MaxLength); issue != nil {
+			problems = append(problems, issue.Problems...)
+			if issue.overflow() {
+				problems = append(problems, fmt.Sprintf("%d characters, over the limit of %d", issue.Length, issue.MaxLength))
+			}
+		}
+
+		unit.Segment.State = xliffStateTranslated
+		if len(problems) > 0 {
+			unit.Segment.SubState = xliffSubStateNeedsReview
+			unit.addNote("go-multilingual", strings.Join(problems, "; "))
+		}
+		units = append(units, unit)
+	})
+	return units
+}
+
+// 검토된 XLIFF를 대상 로케일 파일에 반영하고 승인된 키를 기록한다.
+func importXLIFF(opts *translateOptions, paths []string) error {
+	sourceFiles, err := discoverSourceFiles(opts.Source, opts.SourceLang, opts.Include, opts.ExcludeFiles)
+	if err != nil {
+		return err
+	}
+	byPath := make(map[string]SourceFile)
+	for _, file := range sourceFiles {
+		byPath[file.RelPath] = file
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", path, err)
+		}
+		var doc xliffDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("error parsing XLIFF 2.0 in %s: %v", path, err)
+		}
+		if doc.Version != "2.0" {
+			return fmt.Errorf("%s: unsupported XLIFF version %q (expected 2.0)", path, doc.Version)
+		}
+		if doc.TrgLang == "" {
+			return fmt.Errorf("%s: <xliff> has no trgLang", path)
+		}
+		// 파일을 쓰기 전에 가져올 대상 값을 모두 확인한다
+		for _, xf := range doc.Files {
+			for _, unit := range xf.Units {
+				if ok, _ := unit.importable(); !ok {
+					continue
+				}
+				if _, err := unit.Segment.Target.text(); err != nil {
+					return fmt.Errorf("%s: %s unit %s: %v", path, xf.Original, unit.Name, err)
+				}
+			}
+		}
+
+		for _, xf := range doc.Files {
+			file, ok := byPath[xf.Original]
+			if !ok {
+				return fmt.Errorf("%s: source file %q not found in %s", path, xf.Original, opts.Source)
+			}
+			if err := importXLIFFFile(xf, doc.TrgLang, file, opts); err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+		}
+	}
+	return opts.approvals.Save()
+}
+
+func importXLIFFFile(xf xliffFile, lang string, file SourceFile, opts *translateOptions) error {
+	job := prepareJob(TranslationJob{
+		SourceLang: opts.SourceLang,
+		TargetLang: lang,
+		File:       file.RelPath,
+		Content:    file.Content,
+		Context:    file.Context,
+		Document:   file.Document,
+	}, opts)
+	sourceLeaves := flattenTree(job.Content)
+
+	outputFile := targetFilePath(opts.OutDir, lang, file.RelPath)
+	existing, err := readLocaleTree(outputFile, lang)
+	if err != nil {
+		return fmt.Errorf("error reading existing translation: %v", err)
+	}
+	existingLeaves := flattenTree(existing)
+
+	imported := make(map[string]interface{})
+	approvedCount, unapproved, skipped := 0, 0, 0
+	for _, unit := range xf.Units {
+		path := unit.Name
+		source, ok := sourceLeaves[path].(string)
+		if !ok {
+			log.Printf("Warning: %s (%s): key %s is not in the source, skipped", lang, file.RelPath, path)
+			skipped++
+			continue
+		}
+		ok, approved := unit.importable()
+		if !ok {
+			skipped++
+			continue
+		}
+		translated, err := unit.Segment.Target.text()
+		if err != nil {
+			return fmt.Errorf("unit %s: %v", path, err)
+		}
+		imported[path] = translated
+		if issue := checkTranslatedValue(path, source, translated, lang, opts.glossary, job.Context[path].MaxLength); issue != nil {
+			log.Printf("Warning: %s (%s): %s", lang, file.RelPath, issue)
+		}
+		if approved {
+			opts.approvals.approve(lang, file.RelPath, path, source)
+			approvedCount++
+		} else {
+			unapproved++
+		}
+	}
+
+	// 가져온 값 > 기존 번역 순으로 채우고, 둘 다 없는 문자열 키는 빼서 다음 번역 실행에서 요청되게 한다
+	merged, _ := rebuildTree(job.Content, "", func(path string) (interface{}, bool) {
+		if value, ok := imported[path]; ok {
+			return value, true
+		}
+		if value, ok := existingLeaves[path]; ok {
+			return value, true
+		}
+		value := sourceLeaves[path]
+		if _, isText := value.(string); isText {
+			return nil, false
+		}
+		return value, true
+	})
+	if _, err := writeLocaleFile(outputFile, job.Document, merged, lang); err != nil {
+		return err
+	}
+	fmt.Printf("%s (%s): imported %d keys (%d approved), skipped %d → %s\n", lang, file.RelPath, len(imported), approvedCount, skipped, outputFile)
+	if unapproved > 0 {
+		fmt.Printf("  %d keys were imported in state=\"translated\" and are not approved; a translate run without --incremental may overwrite them (mark them reviewed or final to keep them)\n", unapproved)
+	}
+	return nil
+}
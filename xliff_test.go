@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXLIFFRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "locales", "en", "common.json"),
+		`{"greeting":"Hello {name}","steps":["Open","Go"],"bye":"Bye","fresh":"New"}`)
+	writeTestFile(t, filepath.Join(dir, "locales", "fr", "common.json"),
+		`{"greeting":"Bonjour {name}","steps":["Ouvrir","Aller"],"bye":"Au revoir {x}"}`)
+
+	approvals, err := loadApprovals(filepath.Join(dir, "approved.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &translateOptions{
+		Source:      filepath.Join(dir, "locales", "en"),
+		SourceLang:  "en",
+		Include:     []string{"**/*.json"},
+		OutDir:      filepath.Join(dir, "locales"),
+		SnapshotDir: filepath.Join(dir, "snapshots"),
+		Targets:     []string{"fr"},
+		approvals:   approvals,
+	}
+
+	xliffDir := filepath.Join(dir, "xliff")
+	if err := exportXLIFF(opts, xliffDir); err != nil {
+		t.Fatal(err)
+	}
+	path := xliffPath(xliffDir, "fr")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc xliffDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "2.0" || doc.SrcLang != "en" || doc.TrgLang != "fr" {
+		t.Fatalf("root = version %q srcLang %q trgLang %q", doc.Version, doc.SrcLang, doc.TrgLang)
+	}
+	if len(doc.Files) != 1 || doc.Files[0].Original != "common.json" {
+		t.Fatalf("files = %+v", doc.Files)
+	}
+
+	exported := []struct {
+		name         string
+		wantState    string
+		wantSubState string
+		wantTarget   string
+	}{
+		{"/greeting", xliffStateTranslated, "", "Bonjour {name}"},
+		{"/steps/0", xliffStateTranslated, "", "Ouvrir"},
+		{"/steps/1", xliffStateTranslated, "", "Aller"},
+		{"/bye", xliffStateTranslated, xliffSubStateNeedsReview, "Au revoir {x}"},
+		{"/fresh", xliffStateInitial, "", ""},
+	}
+	units := make(map[string]*xliffUnit)
+	for i := range doc.Files[0].Units {
+		units[doc.Files[0].Units[i].Name] = &doc.Files[0].Units[i]
+	}
+	for _, tt := range exported {
+		t.Run("export "+tt.name, func(t *testing.T) {
+			unit, ok := units[tt.name]
+			if !ok {
+				t.Fatalf("no unit named %s", tt.name)
+			}
+			segment := unit.Segment
+			if segment.State != tt.wantState || segment.SubState != tt.wantSubState {
+				t.Errorf("state = %q/%q, want %q/%q", segment.State, segment.SubState, tt.wantState, tt.wantSubState)
+			}
+			target := ""
+			if segment.Target != nil {
+				var err error
+				if target, err = segment.Target.text(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if target != tt.wantTarget {
+				t.Errorf("target = %q, want %q", target, tt.wantTarget)
+			}
+		})
+	}
+
+	// 번역가의 편집: 검토, 승인, 새 번역. /bye는 검토가 필요한 상태로 남겨 둔다.
+	edit := func(name, state, target string) {
+		units[name].Segment.State = state
+		units[name].Segment.Target = newXLIFFTarget(target)
+	}
+	edit("/greeting", xliffStateReviewed, "Salut {name}")
+	edit("/steps/1", xliffStateFinal, "Va")
+	edit("/fresh", xliffStateTranslated, "Nouveau")
+	edited, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, edited, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := importXLIFF(opts, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	merged, err := readLocaleTree(filepath.Join(dir, "locales", "fr", "common.json"), "fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"greeting":"Salut {name}","steps":["Ouvrir","Va"],"bye":"Au revoir {x}","fresh":"Nouveau"}`
+	if got := mustJSON(t, merged); got != want {
+		t.Errorf("imported file = %s, want %s", got, want)
+	}
+
+	reloaded, err := loadApprovals(filepath.Join(dir, "approved.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	approved := reloaded.forFile("fr", "common.json")
+	for _, tt := range []struct {
+		name string
+		want bool
+	}{
+		{"/greeting", true},
+		{"/steps/1", true},
+		{"/steps/0", false},
+		{"/fresh", false},
+		{"/bye", false},
+	} {
+		if _, ok := approved[tt.name]; ok != tt.want {
+			t.Errorf("approved[%s] = %v, want %v", tt.name, ok, tt.want)
+		}
+	}
+
+	// 승인된 키는 다음 내보내기에서 final이 된다
+	if err := exportXLIFF(opts, xliffDir); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc = xliffDocument{}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, unit := range doc.Files[0].Units {
+		if unit.Name == "/greeting" && unit.Segment.State != xliffStateFinal {
+			t.Errorf("/greeting state after approval = %q, want %q", unit.Segment.State, xliffStateFinal)
+		}
+	}
+}
+
+func TestXLIFFImportRejectsVersion12(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "locales", "en", "common.json"), `{"a":"A"}`)
+	path := filepath.Join(dir, "fr.xlf")
+	writeTestFile(t, path, `<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2"><file original="common.json" source-language="en" target-language="fr" datatype="plaintext"><body/></file></xliff>`)
+
+	opts := &translateOptions{Source: filepath.Join(dir, "locales", "en"), SourceLang: "en", Include: []string{"**/*.json"}, OutDir: filepath.Join(dir, "locales")}
+	if err := importXLIFF(opts, []string{path}); err == nil {
+		t.Fatal("importing XLIFF 1.2 succeeded, want an error")
+	}
+}
+
+func TestXLIFFImportTargets(t *testing.T) {
+	const header = `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="fr"><file id="f1" original="common.json">`
+	tests := []struct {
+		name    string
+		units   string
+		want    string // 가져온 뒤의 대상 파일 (비어 있으면 파일을 쓰지 않아야 함)
+		wantErr string
+	}{
+		{
+			name:  "escaped markup and CDATA are plain text",
+			units: `<unit id="u1" name="/a"><segment state="reviewed"><source/><target>Bonjour &lt;b&gt;monde&lt;/b&gt;</target></segment></unit><unit id="u2" name="/b"><segment state="final"><source/><target><![CDATA[Tom & Jerry]]></target></segment></unit>`,
+			want:  `{"a":"Bonjour <b>monde</b>","b":"Tom & Jerry"}`,
+		},
+		{
+			name:    "inline elements are rejected",
+			units:   `<unit id="u1" name="/a"><segment state="reviewed"><source/><target>Bonjour <pc id="1">monde</pc></target></segment></unit><unit id="u2" name="/b"><segment state="final"><source/><target>Tom et Jerry</target></segment></unit>`,
+			wantErr: "inline element <pc>",
+		},
+		{
+			name:  "inline elements in skipped units are ignored",
+			units: `<unit id="u1" name="/a"><segment state="initial"><source/><target><ph id="1"/></target></segment></unit><unit id="u2" name="/b"><segment state="final"><source/><target>Tom et Jerry</target></segment></unit>`,
+			want:  `{"b":"Tom et Jerry"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFile(t, filepath.Join(dir, "locales", "en", "common.json"), `{"a":"Hello <b>world</b>","b":"Tom & Jerry"}`)
+			path := filepath.Join(dir, "fr.xlf")
+			writeTestFile(t, path, header+tt.units+`</file></xliff>`)
+			approvals, err := loadApprovals(filepath.Join(dir, "approved.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := &translateOptions{Source: filepath.Join(dir, "locales", "en"), SourceLang: "en", Include: []string{"**/*.json"}, OutDir: filepath.Join(dir, "locales"), approvals: approvals}
+
+			err = importXLIFF(opts, []string{path})
+			output := filepath.Join(dir, "locales", "fr", "common.json")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if _, err := os.Stat(output); !os.IsNotExist(err) {
+					t.Errorf("target file was written despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			merged, err := readLocaleTree(output, "fr")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mustJSON(t, merged); got != mustJSON(t, mustParse(t, tt.want)) {
+				t.Errorf("imported file = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXLIFFTargetEscaping(t *testing.T) {
+	for _, text := range []string{"Tom & Jerry", "Bonjour <b>{name}</b>", "a\nb \"c\"", ""} {
+		target := newXLIFFTarget(text)
+		data, err := xml.Marshal(xliffSegment{Source: text, Target: target})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var segment xliffSegment
+		if err := xml.Unmarshal(data, &segment); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := segment.Target.text(); err != nil || got != text {
+			t.Errorf("%q round trip = %q, %v (%s)", text, got, err, data)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// XLIFF 2.0 (.xlf, .xliff)
+// 키는 <unit>의 id이고 값은 <source>다. segment가 여러 개인 unit은 segment별 배열이 된다.
+// 대상 파일은 소스 파일의 텍스트에 trgLang, <target>, segment의 state만 넣어 쓰므로 주석, 메타데이터와 서식이 그대로 남는다.
+// 인라인 코드(<ph/>, <pc>...</pc> 등)는 태그 그대로 두어 번역 후에도 보존되었는지 태그 검사로 확인한다.
+// <notes>의 note는 맥락으로 모델에 전달한다.
+type xliffFormat struct{}
+
+type xliffFileUnit struct {
+	id       string
+	notes    []string
+	segments []*xliffFileSegment
+}
+
+type xliffFileSegment struct {
+	tag       [2]int  // <segment ...> 시작 태그의 바이트 범위
+	source    [2]int  // <source> 내용의 바이트 범위
+	sourceTag [2]int  // <source> 요소 전체의 바이트 범위
+	target    *[2]int // <target> 요소 전체의 바이트 범위 (없으면 nil)
+	inner     [2]int  // <target> 내용의 바이트 범위
+}
+
+type xliffSource struct {
+	data  []byte
+	root  [2]int // <xliff ...> 시작 태그의 바이트 범위
+	units []*xliffFileUnit
+}
+
+var (
+	// XLIFF 2.0 인라인 요소 (번역 값에서 태그 그대로 쓴다)
+	xliffInlinePattern = regexp.MustCompile(`<\s*/?\s*(?:ph|pc|sc|ec|mrk|sm|em|cp)\b[^<>]*>`)
+	xliffStatePattern  = regexp.MustCompile(`\sstate\s*=\s*("[^"]*"|'[^']*')`)
+	xliffTrgLangAttr   = regexp.MustCompile(`\strgLang\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+func (xliffFormat) Name() string { return "xliff" }
+
+func (xliffFormat) Parse(data []byte, lang string) (*localeDocument, error) {
+	source, err := scanXLIFF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &localeDocument{Context: make(contextNotes), source: source}
+	content := newOrderedMap()
+	for _, unit := range source.units {
+		texts := make([]interface{}, len(unit.segments))
+		for i, segment := range unit.segments {
+			texts[i] = unescapeXLIFF(string(data[segment.source[0]:segment.source[1]]))
+		}
+		if len(texts) == 1 {
+			content.Set(unit.id, texts[0])
+		} else {
+			content.Set(unit.id, texts)
+		}
+		if len(unit.notes) > 0 {
+			doc.Context[joinPointer("", unit.id)] = keyContext{Description: strings.Join(unit.notes, " ")}
+		}
+	}
+	doc.Content = content
+	return doc, nil
+}
+
+// 기존 대상 파일의 <target> (대상이 없는 segment가 있는 unit은 번역되지 않은 것으로 본다)
+func (xliffFormat) ReadTarget(data []byte, lang string) (interface{}, error) {
+	source, err := scanXLIFF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	content := newOrderedMap()
+	for _, unit := range source.units {
+		var texts []interface{}
+		for _, segment := range unit.segments {
+			if segment.target == nil || segment.inner[0] == segment.inner[1] {
+				break
+			}
+			texts = append(texts, unescapeXLIFF(string(data[segment.inner[0]:segment.inner[1]])))
+		}
+		switch {
+		case len(texts) != len(unit.segments):
+		case len(texts) == 1:
+			content.Set(unit.id, texts[0])
+		default:
+			content.Set(unit.id, texts)
+		}
+	}
+	return content, nil
+}
+
+// segment가 있는 unit과 unit의 note를 찾는다.
+func scanXLIFF(data []byte) (*xliffSource, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+
+	source := &xliffSource{data: data}
+	seen := make(map[string]bool)
+	var unit *xliffFileUnit
+	var segment *xliffFileSegment
+	var note *strings.Builder
+	var target int // <target> 시작 위치
+	depth := 0
+
+	for {
+		offset := int(dec.InputOffset())
+		token, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset())
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case depth == 1:
+				if t.Name.Local != "xliff" {
+					return nil, fmt.Errorf("root element must be <xliff>, got <%s>", t.Name.Local)
+				}
+				if version := xmlAttr(t, "version"); version != "2.0" {
+					return nil, fmt.Errorf("unsupported XLIFF version %q (expected 2.0)", version)
+				}
+				source.root = [2]int{offset, end}
+			case t.Name.Local == "unit":
+				unit = &xliffFileUnit{id: xmlAttr(t, "id")}
+				if unit.id == "" {
+					return nil, fmt.Errorf("<unit> without an id attribute")
+				}
+				if seen[unit.id] {
+					return nil, fmt.Errorf("duplicate unit id %q", unit.id)
+				}
+				seen[unit.id] = true
+			case unit != nil && segment == nil && t.Name.Local == "note":
+				note = &strings.Builder{}
+			case unit != nil && t.Name.Local == "segment":
+				segment = &xliffFileSegment{tag: [2]int{offset, end}}
+			case segment != nil && t.Name.Local == "source":
+				segment.sourceTag[0] = offset
+				segment.source = [2]int{end, end}
+			case segment != nil && t.Name.Local == "target":
+				target = offset
+				segment.inner = [2]int{end, end}
+			}
+		case xml.EndElement:
+			switch {
+			case t.Name.Local == "unit" && unit != nil:
+				if len(unit.segments) > 0 {
+					source.units = append(source.units, unit)
+				}
+				unit = nil
+			case t.Name.Local == "note" && note != nil:
+				if text := strings.Join(strings.Fields(note.String()), " "); text != "" {
+					unit.notes = append(unit.notes, text)
+				}
+				note = nil
+			case t.Name.Local == "segment" && segment != nil:
+				unit.segments = append(unit.segments, segment)
+				segment = nil
+			case t.Name.Local == "source" && segment != nil:
+				segment.source[1] = offset
+				segment.sourceTag[1] = end
+			case t.Name.Local == "target" && segment != nil:
+				segment.inner[1] = offset
+				segment.target = &[2]int{target, end}
+			}
+			depth--
+		case xml.CharData:
+			if note != nil {
+				note.Write(t)
+			}
+		}
+	}
+	if depth != 0 || source.root[1] == 0 {
+		return nil, fmt.Errorf("incomplete XLIFF document")
+	}
+	return source, nil
+}
+
+func xmlAttr(t xml.StartElement, name string) string {
+	for _, attr := range t.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func (xliffFormat) Render(doc *localeDocument, content interface{}, lang string) ([]byte, error) {
+	source := doc.source.(*xliffSource)
+	data := source.data
+	translated, _ := content.(*orderedMap)
+	if translated == nil {
+		translated = newOrderedMap()
+	}
+
+	patch := &textPatch{data: data}
+	root := string(data[source.root[0]:source.root[1]])
+	patch.replace(source.root[0], source.root[1], setXMLAttr(root, xliffTrgLangAttr, "trgLang", lang))
+
+	for _, unit := range source.units {
+		value, _ := translated.Get(unit.id)
+		texts, ok := value.([]interface{})
+		if !ok {
+			texts = []interface{}{value}
+		}
+		for i, segment := range unit.segments {
+			var text string
+			isText := false
+			if i < len(texts) {
+				text, isText = texts[i].(string)
+			}
+			tag := string(data[segment.tag[0]:segment.tag[1]])
+			if !isText {
+				// 번역되지 않은 segment는 대상 없이 둔다
+				if xliffStatePattern.MatchString(tag) {
+					patch.replace(segment.tag[0], segment.tag[1], setXMLAttr(tag, xliffStatePattern, "state", xliffStateInitial))
+				}
+				if segment.target != nil {
+					patch.remove(segment.target[0], segment.target[1])
+				}
+				continue
+			}
+
+			patch.replace(segment.tag[0], segment.tag[1], setXMLAttr(tag, xliffStatePattern, "state", xliffStateTranslated))
+			element := "<target>" + escapeXLIFF(text) + "</target>"
+			if segment.target != nil {
+				patch.replace(segment.target[0], segment.target[1], element)
+				continue
+			}
+			// <source>와 같은 들여쓰기로 바로 뒤에 넣는다
+			indent := ""
+			start := segment.sourceTag[0]
+			for start > 0 && (data[start-1] == ' ' || data[start-1] == '\t') {
+				start--
+				indent = string(data[start]) + indent
+			}
+			if start == 0 || data[start-1] == '\n' {
+				element = "\n" + indent + element
+			}
+			patch.replace(segment.sourceTag[1], segment.sourceTag[1], element)
+		}
+	}
+	return patch.finish(), nil
+}
+
+// 시작 태그의 속성 값을 바꾸고, 없으면 태그 끝에 더한다.
+func setXMLAttr(tag string, pattern *regexp.Regexp, name, value string) string {
+	attr := fmt.Sprintf(` %s="%s"`, name, html.EscapeString(value))
+	if pattern.MatchString(tag) {
+		return pattern.ReplaceAllLiteralString(tag, attr)
+	}
+	end := len(tag) - 1
+	if strings.HasSuffix(tag, "/>") {
+		end--
+	}
+	return tag[:end] + attr + tag[end:]
+}
+
+// 인라인 요소 밖의 XML 엔티티를 푼다.
+func unescapeXLIFF(raw string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range xliffInlinePattern.FindAllStringIndex(raw, -1) {
+		b.WriteString(html.UnescapeString(raw[last:loc[0]]))
+		b.WriteString(raw[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(html.UnescapeString(raw[last:]))
+	return b.String()
+}
+
+var xliffTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// 인라인 요소 밖의 텍스트에 XML 이스케이프를 적용한다.
+func escapeXLIFF(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range xliffInlinePattern.FindAllStringIndex(text, -1) {
+		b.WriteString(xliffTextEscaper.Replace(text[last:loc[0]]))
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(xliffTextEscaper.Replace(text[last:]))
+	return b.String()
+}
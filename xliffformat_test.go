@@ -0,0 +1,136 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const testXLIFFSource = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en">
+  <file id="f1">
+    <!-- 화면 제목 -->
+    <unit id="title">
+      <notes>
+        <note category="description">Page title</note>
+      </notes>
+      <segment>
+        <source>Tom &amp; Jerry</source>
+      </segment>
+    </unit>
+    <unit id="greeting">
+      <segment state="initial">
+        <source>Hello <ph id="1" equiv="INTERPOLATION" disp="{{ name }}"/>!</source>
+        <target>stale</target>
+      </segment>
+    </unit>
+    <unit id="intro">
+      <segment><source>One.</source></segment>
+      <ignorable><source> </source></ignorable>
+      <segment><source>Two.</source></segment>
+    </unit>
+  </file>
+</xliff>
+`
+
+func TestXLIFFFormatParse(t *testing.T) {
+	doc, err := parseLocaleFile("messages.xlf", []byte(testXLIFFSource), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"title":"Tom \u0026 Jerry","greeting":"Hello \u003cph id=\"1\" equiv=\"INTERPOLATION\" disp=\"{{ name }}\"/\u003e!","intro":["One.","Two."]}`
+	if got := mustJSON(t, doc.Content); got != want {
+		t.Errorf("content = %s, want %s", got, want)
+	}
+	if got := doc.Context["/title"].Description; got != "Page title" {
+		t.Errorf("context = %q, want %q", got, "Page title")
+	}
+}
+
+func TestXLIFFFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		translated  string
+		wantTarget  string // ReadTarget 결과
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:       "all translated",
+			translated: `{"title":"Tom & Jerry FR","greeting":"Bonjour <ph id=\"1\" equiv=\"INTERPOLATION\" disp=\"{{ name }}\"/> !","intro":["Un.","Deux."]}`,
+			wantTarget: `{"title":"Tom \u0026 Jerry FR","greeting":"Bonjour \u003cph id=\"1\" equiv=\"INTERPOLATION\" disp=\"{{ name }}\"/\u003e !","intro":["Un.","Deux."]}`,
+			contains: []string{
+				`trgLang="fr"`,
+				"<!-- 화면 제목 -->",
+				"<note category=\"description\">Page title</note>",
+				"<segment state=\"translated\">\n        <source>Tom &amp; Jerry</source>\n        <target>Tom &amp; Jerry FR</target>",
+				`<target>Bonjour <ph id="1" equiv="INTERPOLATION" disp="{{ name }}"/> !</target>`,
+				"<segment state=\"translated\"><source>One.</source><target>Un.</target></segment>",
+				"<ignorable><source> </source></ignorable>",
+			},
+			notContains: []string{"stale"},
+		},
+		{
+			name:       "missing keys and segments are left without a target",
+			translated: `{"title":"Titre","intro":["Un."]}`,
+			wantTarget: `{"title":"Titre"}`,
+			contains: []string{
+				`<target>Titre</target>`,
+				"<segment state=\"initial\">\n        <source>Hello",
+				"<segment state=\"translated\"><source>One.</source><target>Un.</target></segment>",
+				"<segment><source>Two.</source></segment>",
+			},
+			notContains: []string{"stale"},
+		},
+	}
+
+	doc, err := parseLocaleFile("messages.xlf", []byte(testXLIFFSource), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := doc.render(mustParse(t, tt.translated), "fr")
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := string(data)
+			for _, s := range tt.contains {
+				if !strings.Contains(out, s) {
+					t.Errorf("output does not contain %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.notContains {
+				if strings.Contains(out, s) {
+					t.Errorf("output contains %q:\n%s", s, out)
+				}
+			}
+
+			target, err := xliffFormat{}.ReadTarget(data, "fr")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mustJSON(t, target); got != tt.wantTarget {
+				t.Errorf("ReadTarget = %s, want %s", got, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestXLIFFFormatErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"XLIFF 1.2", `<xliff version="1.2"><file><body/></file></xliff>`},
+		{"wrong root", `<resources/>`},
+		{"unit without id", `<xliff version="2.0"><file id="f"><unit><segment><source>a</source></segment></unit></file></xliff>`},
+		{"duplicate unit id", `<xliff version="2.0"><file id="f"><unit id="a"><segment><source>a</source></segment></unit><unit id="a"><segment><source>b</source></segment></unit></file></xliff>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (xliffFormat{}).Parse([]byte(tt.data), "en"); err == nil {
+				t.Error("Parse succeeded, want an error")
+			}
+		})
+	}
+}
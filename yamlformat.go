@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML (Rails i18n 포함)
+// 최상위 키가 하나뿐이고 파일 언어 코드와 같으면(Rails의 "en:") 그 아래를 트리로 쓰고, 대상 파일에서는 대상 언어 코드로 바꾼다.
+// 키에 붙은 주석은 맥락으로 모델에 전달하고 대상 파일에도 그대로 남긴다.
+type yamlFormat struct{}
+
+type yamlSource struct {
+	doc    *yaml.Node // 문서 노드
+	root   *yaml.Node // 트리에 해당하는 매핑 노드
+	indent int
+}
+
+var yamlIndentPattern = regexp.MustCompile(`(?m)^( +)\S`)
+
+func (yamlFormat) Name() string { return "yaml" }
+
+func (yamlFormat) Parse(data []byte, lang string) (*localeDocument, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("top-level value must be a mapping")
+	}
+
+	source := &yamlSource{doc: &doc, root: doc.Content[0], indent: 2}
+	if root := source.root; len(root.Content) == 2 && root.Content[0].Value == lang && root.Content[1].Kind == yaml.MappingNode {
+		source.root = root.Content[1]
+	}
+	if match := yamlIndentPattern.FindSubmatch(data); match != nil {
+		source.indent = len(match[1])
+	}
+
+	parsed := &localeDocument{Context: make(contextNotes), Plurals: make(map[string]bool), source: source}
+	content, err := yamlValue(source.root, "", parsed)
+	if err != nil {
+		return nil, err
+	}
+	parsed.Content = content
+	return parsed, nil
+}
+
+// YAML 노드 → 트리 값 (별칭은 원래 노드로 풀어서 읽는다)
+func yamlValue(node *yaml.Node, path string, doc *localeDocument) (interface{}, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		m := newOrderedMap()
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			childPath := joinPointer(path, keyNode.Value)
+			if comment := yamlComment(keyNode); comment != "" {
+				doc.Context[childPath] = keyContext{Description: comment}
+			}
+			value, err := yamlValue(valueNode, childPath, doc)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(keyNode.Value, value)
+		}
+		if isPluralForms(m) {
+			doc.Plurals[path] = true
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		items := make([]interface{}, len(node.Content))
+		for i, item := range node.Content {
+			value, err := yamlValue(item, joinPointer(path, strconv.Itoa(i)), doc)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = value
+		}
+		return items, nil
+	case yaml.ScalarNode:
+		var value interface{}
+		switch node.ShortTag() {
+		case "!!int", "!!float":
+			var n float64
+			if err := node.Decode(&n); err != nil {
+				return nil, err
+			}
+			value = n
+		case "!!bool":
+			var b bool
+			if err := node.Decode(&b); err != nil {
+				return nil, err
+			}
+			value = b
+		case "!!null":
+			value = nil
+		default:
+			value = node.Value
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+func yamlComment(node *yaml.Node) string {
+	var lines []string
+	for _, comment := range []string{node.HeadComment, node.LineComment} {
+		for _, line := range strings.Split(comment, "\n") {
+			if line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#")); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+func (yamlFormat) Render(doc *localeDocument, content interface{}, lang string) ([]byte, error) {
+	source := doc.source.(*yamlSource)
+	root := yamlNode(content, source.root)
+
+	// Rails 스타일이면 최상위 언어 키를 대상 언어로 바꾼다
+	top := root
+	if source.root != source.doc.Content[0] {
+		wrapper := *source.doc.Content[0]
+		key := *wrapper.Content[0]
+		key.Value = lang
+		wrapper.Content = []*yaml.Node{&key, root}
+		top = &wrapper
+	}
+	document := *source.doc
+	document.Content = []*yaml.Node{top}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(source.indent)
+	if err := enc.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// 트리 값 → YAML 노드
+// 소스에 같은 키의 노드가 있으면 스타일과 주석을 복사해 값만 바꾼다.
+func yamlNode(value interface{}, template *yaml.Node) *yaml.Node {
+	if template != nil && template.Kind == yaml.AliasNode {
+		template = template.Alias
+	}
+	switch v := value.(type) {
+	case *orderedMap:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if template != nil && template.Kind == yaml.MappingNode {
+			copied := *template
+			copied.Anchor = ""
+			node = &copied
+		}
+		var content []*yaml.Node
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+			var childTemplate *yaml.Node
+			if template != nil && template.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(template.Content); i += 2 {
+					if template.Content[i].Value == key {
+						copied := *template.Content[i]
+						keyNode = &copied
+						childTemplate = template.Content[i+1]
+						break
+					}
+				}
+				// 대상 언어에만 있는 복수형 범주는 "other"의 스타일을 따른다
+				if childTemplate == nil && validPluralCategory[key] {
+					for i := 0; i+1 < len(template.Content); i += 2 {
+						if template.Content[i].Value == "other" {
+							childTemplate = template.Content[i+1]
+						}
+					}
+				}
+			}
+			content = append(content, keyNode, yamlNode(child, childTemplate))
+		}
+		node.Content = content
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if template != nil && template.Kind == yaml.SequenceNode {
+			copied := *template
+			copied.Anchor = ""
+			node = &copied
+		}
+		var content []*yaml.Node
+		for i, item := range v {
+			var itemTemplate *yaml.Node
+			if template != nil && template.Kind == yaml.SequenceNode && i < len(template.Content) {
+				itemTemplate = template.Content[i]
+			}
+			content = append(content, yamlNode(item, itemTemplate))
+		}
+		node.Content = content
+		return node
+	}
+
+	node := &yaml.Node{Kind: yaml.ScalarNode}
+	if template != nil && template.Kind == yaml.ScalarNode {
+		copied := *template
+		copied.Anchor = ""
+		node = &copied
+	}
+	text, ok := value.(string)
+	if !ok {
+		// 숫자, 불리언, null은 소스 값을 그대로 쓴다
+		if node.Tag == "" {
+			if err := node.Encode(value); err != nil {
+				node.Tag, node.Value = "!!str", fmt.Sprint(value)
+			}
+		}
+		return node
+	}
+	// 원래 따옴표 스타일은 유지하되, 여러 줄 값은 블록 스타일로 쓴다
+	node.Tag, node.Value = "!!str", text
+	if strings.Contains(text, "\n") && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+		node.Style = yaml.LiteralStyle
+	}
+	return node
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestYAMLFormatRoundTrip(t *testing.T) {
+	runFormatRoundTrips(t, []formatRoundTrip{
+		{
+			name:        "Rails language key is renamed and comments are kept",
+			path:        "en.yml",
+			source:      "en:\n  # greeting\n  a: Hi\n  n:\n    b: B\n  c: C\n",
+			wantContent: `{"a":"Hi","n":{"b":"B"},"c":"C"}`,
+			translated:  `{"a":"Salut","n":{"b":"Bé"}}`,
+			want:        "fr:\n  # greeting\n  a: Salut\n  n:\n    b: Bé\n",
+		},
+		{
+			name:        "plain YAML with a list",
+			path:        "common.yaml",
+			source:      "title: Home\nsteps:\n  - Open\n  - Go\n",
+			wantContent: `{"title":"Home","steps":["Open","Go"]}`,
+			translated:  `{"title":"Accueil","steps":["Ouvrir","Aller"]}`,
+			want:        "title: Accueil\nsteps:\n  - Ouvrir\n  - Aller\n",
+		},
+	})
+}